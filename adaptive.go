@@ -0,0 +1,86 @@
+package collections
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// adaptiveThreshold is the length at which an AdaptiveIntList leaves the plain linked list.
+const adaptiveThreshold = 4096
+
+var _ IntList = (*AdaptiveIntList)(nil)
+
+// AdaptiveIntList starts as a ConcurrentIntList and promotes itself to a ShardedIntList once its
+// length crosses adaptiveThreshold, so callers get short walks for large lists without tuning.
+// Reads are lock-free on the current implementation. Writes share mu, the promotion takes it
+// exclusively, copies the frozen contents and swaps the implementation before writes resume.
+type AdaptiveIntList struct {
+	mu        sync.RWMutex
+	impl      atomic.Value
+	threshold int
+}
+
+// adaptiveImpl gives atomic.Value a single concrete type to store.
+type adaptiveImpl struct {
+	IntList
+}
+
+func NewAdaptiveIntList() *AdaptiveIntList {
+	a := &AdaptiveIntList{threshold: adaptiveThreshold}
+	a.impl.Store(adaptiveImpl{NewConcurrentIntList()})
+	return a
+}
+
+func (a *AdaptiveIntList) current() IntList {
+	return a.impl.Load().(adaptiveImpl).IntList
+}
+
+// Strategy names the implementation in use, "list" or "sharded".
+func (a *AdaptiveIntList) Strategy() string {
+	if _, ok := a.current().(*ShardedIntList); ok {
+		return "sharded"
+	}
+	return "list"
+}
+
+func (a *AdaptiveIntList) Contains(value int) bool {
+	return a.current().Contains(value)
+}
+
+func (a *AdaptiveIntList) Insert(value int) bool {
+	a.mu.RLock()
+	inserted := a.current().Insert(value)
+	a.mu.RUnlock()
+	if inserted && a.Strategy() == "list" && a.Len() > a.threshold {
+		a.promote()
+	}
+	return inserted
+}
+
+func (a *AdaptiveIntList) Delete(value int) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.current().Delete(value)
+}
+
+func (a *AdaptiveIntList) Range(f func(value int) bool) {
+	a.current().Range(f)
+}
+
+func (a *AdaptiveIntList) Len() int {
+	return a.current().Len()
+}
+
+func (a *AdaptiveIntList) promote() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	list, ok := a.current().(*ConcurrentIntList)
+	if !ok {
+		// promoted by another writer
+		return
+	}
+	// no write is in flight, readers keep walking the old list during the copy
+	sharded := NewShardedIntList(defaultShards)
+	sharded.insertSorted(list.ToSlice())
+	a.impl.Store(adaptiveImpl{sharded})
+}
@@ -0,0 +1,61 @@
+package collections
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestAdaptiveIntList(t *testing.T) {
+	a := NewAdaptiveIntList()
+	if a.Strategy() != "list" {
+		t.Fatal("invalid initial strategy")
+	}
+	for i := 0; i < adaptiveThreshold; i++ {
+		a.Insert(i)
+	}
+	if a.Strategy() != "list" {
+		t.Fatal("promoted before crossing the threshold")
+	}
+
+	// Cross the threshold while other goroutines keep writing.
+	const extra = 1000
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		g := g
+		wg.Add(1)
+		go func() {
+			for i := g; i < extra; i += 4 {
+				if !a.Insert(adaptiveThreshold + i) {
+					panic("invalid insert")
+				}
+				if i%3 == 0 && !a.Delete(i) {
+					panic("invalid delete")
+				}
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+	if a.Strategy() != "sharded" {
+		t.Fatal("not promoted after crossing the threshold")
+	}
+
+	deleted := (extra + 2) / 3
+	if a.Len() != adaptiveThreshold+extra-deleted {
+		t.Fatalf("invalid length %d", a.Len())
+	}
+	pre := -1
+	a.Range(func(value int) bool {
+		if value <= pre {
+			t.Fatal("invalid order after promotion")
+		}
+		if value < extra && value%3 == 0 {
+			t.Fatalf("deleted value %d present", value)
+		}
+		pre = value
+		return true
+	})
+	if !a.Contains(adaptiveThreshold+extra-1) || a.Contains(0) || !a.Contains(1) {
+		t.Fatal("invalid contains after promotion")
+	}
+}
@@ -0,0 +1,86 @@
+package collections
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// binaryChunk is the number of values WriteTo and ReadFrom move per write or read.
+const binaryChunk = 512
+
+var (
+	_ io.WriterTo   = (*ConcurrentIntList)(nil)
+	_ io.ReaderFrom = (*ConcurrentIntList)(nil)
+)
+
+// WriteTo writes the list to w as one binary frame: the count of values as a big-endian uint64,
+// then every value in the list's order as a big-endian int64. The frame is written from a
+// snapshot, so the count always matches the values, through a buffer of binaryChunk values.
+// It returns the number of bytes written and stops at the first write error, a short write
+// without an error is reported as io.ErrShortWrite.
+func (intList *ConcurrentIntList) WriteTo(w io.Writer) (int64, error) {
+	values := intList.ToSlice()
+	buf := make([]byte, 8, 8*binaryChunk)
+	binary.BigEndian.PutUint64(buf, uint64(len(values)))
+	var written int64
+	flush := func() error {
+		n, err := w.Write(buf)
+		written += int64(n)
+		if err == nil && n < len(buf) {
+			err = io.ErrShortWrite
+		}
+		buf = buf[:0]
+		return err
+	}
+	for _, value := range values {
+		if len(buf) == cap(buf) {
+			if err := flush(); err != nil {
+				return written, err
+			}
+		}
+		buf = binary.BigEndian.AppendUint64(buf, uint64(value))
+	}
+	return written, flush()
+}
+
+// ReadFrom reads one frame written by WriteTo from r and replaces the contents of the list by
+// its values, like ReplaceAll, so values out of the domain or beyond the capacity are dropped.
+// It reads exactly the frame and returns the number of bytes read. A frame cut short, before
+// its count or its last value, is an error wrapping io.ErrUnexpectedEOF and leaves the list
+// unchanged, as does a list refusing writes, the frame is then read and ReplaceAll's error
+// returned.
+func (intList *ConcurrentIntList) ReadFrom(r io.Reader) (int64, error) {
+	buf := make([]byte, 8*binaryChunk)
+	n, err := io.ReadFull(r, buf[:8])
+	read := int64(n)
+	if err != nil {
+		return read, fmt.Errorf("collections: frame without a count: %w", unexpected(err))
+	}
+	count := binary.BigEndian.Uint64(buf)
+	// the count is not trusted to size the slice
+	values := make([]int, 0, min(count, binaryChunk))
+	for remaining := count; remaining > 0; {
+		chunk := buf[:8*min(remaining, binaryChunk)]
+		n, err := io.ReadFull(r, chunk)
+		read += int64(n)
+		for i := 0; i+8 <= n; i += 8 {
+			values = append(values, int(int64(binary.BigEndian.Uint64(chunk[i:]))))
+		}
+		if err != nil {
+			return read, fmt.Errorf("collections: frame of %d values ended after %d: %w",
+				count, len(values), unexpected(err))
+		}
+		remaining -= uint64(len(chunk) / 8)
+	}
+	return read, intList.ReplaceAll(values)
+}
+
+// unexpected turns the io.EOF of a read that got nothing into io.ErrUnexpectedEOF, a frame is
+// never expected to end there.
+func unexpected(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
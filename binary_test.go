@@ -0,0 +1,111 @@
+package collections
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestWriteToReadFrom(t *testing.T) {
+	l := NewConcurrentIntList()
+	for i := 0; i < 3*binaryChunk+7; i++ {
+		l.Insert(i*7 - 1000)
+	}
+	l.Insert(math.MinInt)
+	l.Insert(math.MaxInt)
+
+	for _, src := range []*ConcurrentIntList{l, NewConcurrentIntList()} {
+		r, w := io.Pipe()
+		go func() {
+			_, err := src.WriteTo(w)
+			w.CloseWithError(err)
+		}()
+		dst := newListOf(1, 2, 3)
+		n, err := dst.ReadFrom(r)
+		if err != nil || n != int64(8+8*src.Len()) {
+			t.Fatalf("invalid read of %d bytes: %v", n, err)
+		}
+		if !slices.Equal(dst.ToSlice(), src.ToSlice()) || dst.HealthCheck() != nil {
+			t.Fatalf("invalid round trip of %d values, got %d", src.Len(), dst.Len())
+		}
+	}
+
+	// ReadFrom reads exactly one frame
+	var buf bytes.Buffer
+	if n, err := newListOf(5, 6).WriteTo(&buf); err != nil || n != 24 {
+		t.Fatalf("invalid copy of %d bytes: %v", n, err)
+	}
+	buf.WriteString("next")
+	dst := NewConcurrentIntList()
+	if n, err := dst.ReadFrom(&buf); err != nil || n != 24 || !slices.Equal(dst.ToSlice(), []int{5, 6}) {
+		t.Fatalf("invalid read of %d bytes: %v, got %v", n, err, dst.ToSlice())
+	}
+	if buf.String() != "next" {
+		t.Fatalf("invalid read past the frame, left %q", buf.String())
+	}
+
+	// write errors and short writes are reported
+	if n, err := l.WriteTo(&limitedWriter{n: 8 * binaryChunk}); !errors.Is(err, errLimit) || n != 8*binaryChunk {
+		t.Fatalf("invalid write to a failing writer %d: %v", n, err)
+	}
+	if n, err := newListOf(1).WriteTo(shortWriter{}); !errors.Is(err, io.ErrShortWrite) || n != 8 {
+		t.Fatalf("invalid write to a short writer %d: %v", n, err)
+	}
+}
+
+// shortWriter writes half of every buffer without reporting an error.
+type shortWriter struct{}
+
+func (shortWriter) Write(p []byte) (int, error) {
+	return len(p) / 2, nil
+}
+
+func TestReadFromMismatch(t *testing.T) {
+	// the count says 5 values but only 3 follow
+	frame := binary.BigEndian.AppendUint64(nil, 5)
+	for _, value := range []int64{1, 2, 3} {
+		frame = binary.BigEndian.AppendUint64(frame, uint64(value))
+	}
+	l := newListOf(9)
+	n, err := l.ReadFrom(bytes.NewReader(frame))
+	if !errors.Is(err, io.ErrUnexpectedEOF) || n != int64(len(frame)) {
+		t.Fatalf("invalid read of a short frame %d: %v", n, err)
+	}
+	// a partial value
+	if _, err := l.ReadFrom(bytes.NewReader(frame[:8+8+3])); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("invalid read of a partial value %v", err)
+	}
+	for _, partial := range [][]byte{nil, frame[:4]} {
+		if _, err := l.ReadFrom(bytes.NewReader(partial)); !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Fatalf("invalid read of a partial count %v", err)
+		}
+	}
+	if !slices.Equal(l.ToSlice(), []int{9}) {
+		t.Fatalf("list changed by a failed read %v", l.ToSlice())
+	}
+}
+
+func TestReadFromClosed(t *testing.T) {
+	var frame bytes.Buffer
+	if _, err := newListOf(1, 2, 3).WriteTo(&frame); err != nil {
+		t.Fatal(err)
+	}
+	size := int64(frame.Len())
+	l := newListOf(9)
+	l.Close()
+	if err := l.ReplaceAll([]int{1}); !errors.Is(err, ErrClosed) {
+		t.Fatalf("invalid replace of a closed list %v", err)
+	}
+	// the frame is consumed, the list is left unchanged
+	n, err := l.ReadFrom(&frame)
+	if !errors.Is(err, ErrClosed) || n != size || frame.Len() != 0 {
+		t.Fatalf("invalid read into a closed list %d: %v", n, err)
+	}
+	if !slices.Equal(l.ToSlice(), []int{9}) {
+		t.Fatalf("closed list changed by a read %v", l.ToSlice())
+	}
+}
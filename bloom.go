@@ -0,0 +1,108 @@
+package collections
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// bloomFilter is a fixed size Bloom filter safe for concurrent add and mayContain.
+type bloomFilter struct {
+	bits []uint64
+	m    uint64
+	k    uint64
+}
+
+func newBloomFilter(n int, fpRate float64) *bloomFilter {
+	if n < 1 {
+		n = 1
+	}
+	m := uint64(math.Ceil(-float64(n) * math.Log(fpRate) / (math.Ln2 * math.Ln2)))
+	if m < 64 {
+		m = 64
+	}
+	k := uint64(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return &bloomFilter{bits: make([]uint64, (m+63)/64), m: m, k: k}
+}
+
+// bloomHashes returns two independent hashes of value for double hashing.
+func bloomHashes(value int) (uint64, uint64) {
+	// splitmix64 finalizer
+	h := uint64(value) + 0x9E3779B97F4A7C15
+	h = (h ^ (h >> 30)) * 0xBF58476D1CE4E5B9
+	h = (h ^ (h >> 27)) * 0x94D049BB133111EB
+	h ^= h >> 31
+	return h, h>>32 | 1
+}
+
+func (b *bloomFilter) add(value int) {
+	h1, h2 := bloomHashes(value)
+	for i := uint64(0); i < b.k; i++ {
+		bit := (h1 + i*h2) % b.m
+		atomic.OrUint64(&b.bits[bit/64], 1<<(bit%64))
+	}
+}
+
+func (b *bloomFilter) mayContain(value int) bool {
+	h1, h2 := bloomHashes(value)
+	for i := uint64(0); i < b.k; i++ {
+		bit := (h1 + i*h2) % b.m
+		if atomic.LoadUint64(&b.bits[bit/64])&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// WithBloomFilter keeps a Bloom filter sized for expectedN values at fpRate false positives,
+// so Contains answers most misses without walking the list. Insert adds to the filter, Delete
+// doesn't clear it, instead the filter is rebuilt from the contents once expectedN/2 deletes have
+// accumulated. This costs about -expectedN*ln(fpRate)/ln(2)^2 bits and slows down writes a bit.
+func WithBloomFilter(expectedN int, fpRate float64) Option {
+	return func(intList *ConcurrentIntList) {
+		intList.bloomEnabled = true
+		intList.bloomN, intList.bloomFP = expectedN, fpRate
+	}
+}
+
+func (intList *ConcurrentIntList) bloomFilter() *bloomFilter {
+	bloom, _ := intList.bloom.Load().(*bloomFilter)
+	return bloom
+}
+
+// bloomDeleted counts a delete and rebuilds the filter once enough have accumulated. It must
+// not be called during a write.
+func (intList *ConcurrentIntList) bloomDeleted() {
+	if intList.bloomFilter() == nil {
+		return
+	}
+	if atomic.AddInt64(&intList.bloomDeletes, 1) >= int64(max(intList.bloomN/2, 1)) {
+		intList.RebuildBloomFilter()
+	}
+}
+
+// RebuildBloomFilter replaces the Bloom filter by one built from the current contents, dropping
+// the bits of deleted values. It waits for in-flight writes and holds new ones back meanwhile.
+// It is a no-op for a list created without WithBloomFilter.
+func (intList *ConcurrentIntList) RebuildBloomFilter() {
+	if intList.bloomFilter() == nil {
+		return
+	}
+	intList.quiesce(intList.rebuildBloomFilter)
+}
+
+// rebuildBloomFilter must be called while no write is in flight.
+func (intList *ConcurrentIntList) rebuildBloomFilter() {
+	if intList.bloomFilter() == nil {
+		return
+	}
+	bloom := newBloomFilter(max(intList.bloomN, intList.Len()), intList.bloomFP)
+	intList.Range(func(value int) bool {
+		bloom.add(value)
+		return true
+	})
+	intList.bloom.Store(bloom)
+	atomic.StoreInt64(&intList.bloomDeletes, 0)
+}
@@ -0,0 +1,89 @@
+package collections
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestBloomFilter(t *testing.T) {
+	l := NewConcurrentIntList(WithBloomFilter(1000, 0.01))
+	const num = 2000
+	var wg sync.WaitGroup
+	for i := 0; i < num; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			l.Insert(i * 2)
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	// No false negatives, even past the expected size.
+	for i := 0; i < num; i++ {
+		if !l.Contains(i * 2) {
+			t.Fatalf("false negative for %d", i*2)
+		}
+		if l.Contains(i*2 + 1) {
+			t.Fatalf("invalid contains %d", i*2+1)
+		}
+	}
+
+	// Deletes trigger rebuilds, remaining values are still found.
+	for i := 0; i < num; i += 2 {
+		if !l.Delete(i * 2) {
+			t.Fatal("invalid delete")
+		}
+	}
+	for i := 0; i < num; i++ {
+		if l.Contains(i*2) != (i%2 == 1) {
+			t.Fatalf("invalid contains %d after delete", i*2)
+		}
+	}
+	l.RebuildBloomFilter()
+	for i := 1; i < num; i += 2 {
+		if !l.Contains(i * 2) {
+			t.Fatalf("false negative for %d after rebuild", i*2)
+		}
+	}
+
+	// Concurrent inserts and rebuilds never produce a false negative.
+	b := NewConcurrentIntList(WithBloomFilter(16, 0.01))
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			for j := 0; j < 200; j++ {
+				v := i*1000 + j
+				b.Insert(v)
+				b.Insert(v + 500)
+				b.Delete(v + 500)
+				if !b.Contains(v) {
+					panic("false negative under concurrent rebuilds")
+				}
+			}
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+
+	// Without the option the filter stays disabled.
+	NewConcurrentIntList().RebuildBloomFilter()
+}
+
+func benchmarkMisses(b *testing.B, l *ConcurrentIntList) {
+	for i := 0; i < 1e4; i++ {
+		l.Insert(i * 2)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Contains(int(fastrandn(1e4))*2 + 1)
+	}
+}
+
+func BenchmarkContainsMiss(b *testing.B) {
+	benchmarkMisses(b, NewConcurrentIntList())
+}
+
+func BenchmarkContainsMissBloom(b *testing.B) {
+	benchmarkMisses(b, NewConcurrentIntList(WithBloomFilter(1e4, 0.01)))
+}
@@ -0,0 +1,98 @@
+package collections
+
+import (
+	"slices"
+	"sync/atomic"
+)
+
+// installChain links values, which must be ordered and free of duplicates, as the contents of a list that is
+// not shared yet, so no lock is needed.
+func (intList *ConcurrentIntList) installChain(values []int) {
+	intList.root.updateNext(newChain(values))
+	intList.size = int64(len(values))
+}
+
+// newChain links values, which must be strictly ordered, and returns the first node.
+func newChain(values []int) *intNode {
+	var head, tail *intNode
+	for _, value := range values {
+		n := newIntNode(value)
+		if tail == nil {
+			head = n
+		} else {
+			tail.updateNext(n)
+		}
+		tail = n
+	}
+	return head
+}
+
+// compare orders a and b like the list, for the slices sorting functions.
+func (intList *ConcurrentIntList) compare(a, b int) int {
+	switch {
+	case intList.less(a, b):
+		return -1
+	case intList.less(b, a):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// sortedUnique returns a sorted and deduplicated copy of values in the list's order, keeping
+// only values the list accepts: in the domain and within the capacity.
+func (intList *ConcurrentIntList) sortedUnique(values []int) []int {
+	sorted := make([]int, 0, len(values))
+	for _, value := range values {
+		if value = intList.canon(value); intList.inDomain(value) {
+			sorted = append(sorted, value)
+		}
+	}
+	// stable, so that dedupe knows which of tied values came first
+	slices.SortStableFunc(sorted, intList.compare)
+	sorted = intList.dedupe(sorted)
+	if intList.capacity > 0 && int64(len(sorted)) > intList.capacity {
+		sorted = sorted[:intList.capacity]
+	}
+	return sorted
+}
+
+// ReplaceAll atomically replaces the contents of the list by values, in any order. The new
+// chain is built off to the side, then swapped in as one exclusive write, so a concurrent Range
+// sees either the old or the new set. Values out of the domain or beyond the capacity are
+// dropped. Like Swap it waits while the list is frozen and returns ErrClosed or ErrFrozen, doing
+// nothing, if writes are refused.
+func (intList *ConcurrentIntList) ReplaceAll(values []int) error {
+	sorted := intList.sortedUnique(values)
+	head := newChain(sorted)
+	return intList.exclusiveWrite(func() {
+		intList.relink(func() {
+			intList.root.updateNext(head)
+		})
+		atomic.StoreInt64(&intList.size, int64(len(sorted)))
+		intList.versionIncr()
+		intList.rebuildBloomFilter()
+	})
+}
+
+// Builder buffers values in any order and builds an ascending ConcurrentIntList from them in
+// one pass, without the per-insert walks and locking of Insert. The zero value is ready to use,
+// a Builder must not be used concurrently.
+type Builder struct {
+	values []int
+}
+
+// Add buffers value, duplicates are dropped by Build.
+func (b *Builder) Add(value int) {
+	b.values = append(b.values, value)
+}
+
+// Build sorts and deduplicates the buffered values once and returns a new list holding them.
+// The Builder keeps its values and can go on adding and building.
+func (b *Builder) Build() *ConcurrentIntList {
+	slices.Sort(b.values)
+	b.values = slices.Compact(b.values)
+	intList := NewConcurrentIntList()
+	intList.installChain(b.values)
+	return intList
+}
@@ -0,0 +1,125 @@
+package collections
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	var b Builder
+	if l := b.Build(); l.Len() != 0 || l.Front() != nil {
+		t.Fatal("invalid empty build")
+	}
+
+	for _, v := range []int{5, 3, 9, 3, 1, 5, 7} {
+		b.Add(v)
+	}
+	l := b.Build()
+	if got := l.ToSlice(); !slices.Equal(got, []int{1, 3, 5, 7, 9}) || l.Len() != 5 {
+		t.Fatalf("invalid build %v", got)
+	}
+	if !l.IsSorted() || !l.Contains(7) || l.Contains(2) {
+		t.Fatal("invalid build")
+	}
+
+	// The built list is a regular list.
+	if !l.Insert(2) || !l.Delete(9) || l.Len() != 5 {
+		t.Fatal("invalid write on built list")
+	}
+
+	// The builder can go on.
+	b.Add(0)
+	if got := b.Build().ToSlice(); !slices.Equal(got, []int{0, 1, 3, 5, 7, 9}) {
+		t.Fatalf("invalid second build %v", got)
+	}
+}
+
+func benchmarkValues(n int) []int {
+	values := make([]int, n)
+	for i := range values {
+		values[i] = int(fastrandn(uint32(n) * 4))
+	}
+	return values
+}
+
+func BenchmarkBuilder(b *testing.B) {
+	values := benchmarkValues(1e4)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var builder Builder
+		for _, v := range values {
+			builder.Add(v)
+		}
+		builder.Build()
+	}
+}
+
+func BenchmarkRepeatedInsert(b *testing.B) {
+	values := benchmarkValues(1e4)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l := NewConcurrentIntList()
+		for _, v := range values {
+			l.Insert(v)
+		}
+	}
+}
+
+func TestReplaceAll(t *testing.T) {
+	l := NewConcurrentIntList(WithBloomFilter(16, 0.01))
+	for i := 0; i < 10; i++ {
+		l.Insert(i)
+	}
+	v := l.CurrentVersion()
+	l.ReplaceAll([]int{30, 10, 20, 10})
+	if got := l.ToSlice(); !slices.Equal(got, []int{10, 20, 30}) || l.Len() != 3 {
+		t.Fatalf("invalid contents %v", got)
+	}
+	if l.CurrentVersion() == v || l.Contains(1) || !l.Contains(20) {
+		t.Fatal("invalid replace")
+	}
+	if !l.Insert(15) || !l.Delete(30) || !slices.Equal(l.ToSlice(), []int{10, 15, 20}) {
+		t.Fatal("invalid write after replace")
+	}
+
+	// Ordering, domain and capacity of the list are kept.
+	d := NewDescendingIntList(WithDomain(0, 100), WithCapacity(3))
+	d.ReplaceAll([]int{1, 200, 5, 3, 4, -1})
+	if got := d.ToSlice(); !slices.Equal(got, []int{5, 4, 3}) {
+		t.Fatalf("invalid contents %v", got)
+	}
+
+	// Refused on a closed list.
+	d.Close()
+	d.ReplaceAll([]int{1})
+	if d.Len() != 3 {
+		t.Fatal("replace on closed list")
+	}
+
+	// Readers see one coherent set.
+	sets := [][]int{{1, 2, 3}, {4, 5, 6, 7}}
+	l.ReplaceAll(sets[0])
+	var (
+		wg   sync.WaitGroup
+		stop int32
+	)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			for atomic.LoadInt32(&stop) == 0 {
+				got := l.ToSlice()
+				if !slices.Equal(got, sets[0]) && !slices.Equal(got, sets[1]) {
+					panic("range sees a mix of two sets")
+				}
+			}
+			wg.Done()
+		}()
+	}
+	for i := 0; i < 1000; i++ {
+		l.ReplaceAll(sets[i%2])
+	}
+	atomic.StoreInt32(&stop, 1)
+	wg.Wait()
+}
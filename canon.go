@@ -0,0 +1,20 @@
+package collections
+
+// WithCanonicalizer maps every value handed to the list through canon before it is stored or
+// looked up, Insert(v) stores canon(v) and Contains(v) checks canon(v), so values with the same
+// canonical form are duplicates. canon must be deterministic and keep the list's order, never
+// placing canon(a) after canon(b) when a is placed before b, so that sorted input stays sorted.
+// The domain is checked on the canonical value. Values taken from another list, by Swap, Absorb
+// or RemoveAllIn, are used as they are, except for Transfer which moves a single value.
+func WithCanonicalizer(canon func(value int) int) Option {
+	return func(intList *ConcurrentIntList) {
+		intList.canonicalizer = canon
+	}
+}
+
+func (intList *ConcurrentIntList) canon(value int) int {
+	if intList.canonicalizer == nil {
+		return value
+	}
+	return intList.canonicalizer(value)
+}
@@ -0,0 +1,89 @@
+package collections
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWithCanonicalizer(t *testing.T) {
+	// timestamps in seconds bucketed to the minute
+	minute := func(value int) int { return value - value%60 }
+	l := NewConcurrentIntList(WithCanonicalizer(minute))
+
+	if !l.Insert(61) || l.Insert(119) || l.Insert(60) {
+		t.Fatal("invalid insert of the same minute")
+	}
+	if n := l.InsertSorted([]int{0, 30, 125, 150, 179}); n != 2 {
+		t.Fatalf("invalid insert count expected %d, got %d", 2, n)
+	}
+	if got := l.ToSlice(); !slices.Equal(got, []int{0, 60, 120}) {
+		t.Fatalf("invalid contents %v", got)
+	}
+	for _, value := range []int{0, 59, 60, 90, 179} {
+		if !l.Contains(value) || !l.ContainsStrict(value) {
+			t.Fatalf("invalid lookup of %d", value)
+		}
+	}
+	if l.Contains(180) || l.Contains(-61) {
+		t.Fatal("invalid lookup of another minute")
+	}
+	if !l.Delete(100) || l.Contains(60) || l.Delete(60) {
+		t.Fatal("invalid delete of the same minute")
+	}
+	if !l.Move(10, 200) || !l.Contains(239) || l.Contains(0) {
+		t.Fatalf("invalid move, got %v", l.ToSlice())
+	}
+
+	// the domain is checked on the canonical value
+	d := NewConcurrentIntList(WithCanonicalizer(minute), WithDomain(0, 60))
+	if !d.Insert(119) || d.Insert(120) || d.Len() != 1 {
+		t.Fatalf("invalid domain check, got %v", d.ToSlice())
+	}
+
+	l.ReplaceAll([]int{65, 5, 10})
+	if got := l.ToSlice(); !slices.Equal(got, []int{0, 60}) {
+		t.Fatalf("invalid contents after ReplaceAll %v", got)
+	}
+}
+
+func TestShardedCanonicalizer(t *testing.T) {
+	minute := func(value int) int { return value - value%60 }
+	s := NewShardedIntList(16, WithCanonicalizer(minute))
+
+	for value := 0; value < 600; value += 60 {
+		if !s.Insert(value + 1) {
+			t.Fatalf("invalid insert of %d", value+1)
+		}
+		// every second of the minute maps to the shard holding it
+		for second := value; second < value+60; second++ {
+			if s.ShardOf(second) != s.ShardOf(value) || !s.Contains(second) || s.Insert(second) {
+				t.Fatalf("invalid lookup of %d", second)
+			}
+		}
+	}
+	if s.Len() != 10 {
+		t.Fatalf("invalid len expected %d, got %d", 10, s.Len())
+	}
+	if !s.Delete(599) || s.Contains(540) || s.Len() != 9 {
+		t.Fatal("invalid delete of the same minute")
+	}
+}
+
+func TestTransferCanonicalizer(t *testing.T) {
+	minute := func(value int) int { return value - value%60 }
+	from := newListOf(61, 130, 1000)
+	to := NewConcurrentIntList(WithCanonicalizer(minute), WithDomain(0, 599))
+	to.Insert(120)
+
+	if !Transfer(from, to, 61) || from.Contains(61) || !slices.Equal(to.ToSlice(), []int{60, 120}) {
+		t.Fatalf("invalid transfer, got %v and %v", from.ToSlice(), to.ToSlice())
+	}
+	// 130 is the minute 120 in to, 1000 the minute 960 is out of its domain
+	if Transfer(from, to, 130) || Transfer(from, to, 1000) || from.Len() != 2 {
+		t.Fatalf("invalid transfer of a value to refuses, got %v and %v", from.ToSlice(), to.ToSlice())
+	}
+	// looked up by its canonical form in from
+	if !Transfer(to, from, 100) || to.Contains(60) || !from.Contains(60) {
+		t.Fatalf("invalid transfer back, got %v and %v", from.ToSlice(), to.ToSlice())
+	}
+}
@@ -0,0 +1,14 @@
+package collections
+
+import "errors"
+
+// ErrFull is returned when inserting into a list that reached its capacity.
+var ErrFull = errors.New("collections: list is full")
+
+// WithCapacity bounds the number of values the list holds, inserts beyond it are refused with
+// ErrFull. A capacity of 0 means unbounded.
+func WithCapacity(capacity int) Option {
+	return func(intList *ConcurrentIntList) {
+		intList.capacity = int64(capacity)
+	}
+}
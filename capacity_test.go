@@ -0,0 +1,110 @@
+package collections
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestInsertE(t *testing.T) {
+	l := NewConcurrentIntList(WithCapacity(3), WithDomain(0, 100), WithFreezeMode(FreezeReject))
+
+	if err := l.InsertE(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.InsertE(1); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("invalid duplicate insert %v", err)
+	}
+	if err := l.InsertE(101); !errors.Is(err, ErrOutOfDomain) {
+		t.Fatalf("invalid out of domain insert %v", err)
+	}
+
+	l.Insert(2)
+	l.Insert(3)
+	if err := l.InsertE(4); !errors.Is(err, ErrFull) {
+		t.Fatalf("invalid insert into full list %v", err)
+	}
+	if ok, err := l.TryInsert(4); ok || !errors.Is(err, ErrFull) {
+		t.Fatalf("invalid insert into full list %v, %v", ok, err)
+	}
+	// A duplicate is reported as such even when full.
+	if err := l.InsertE(3); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("invalid duplicate insert into full list %v", err)
+	}
+	l.Delete(3)
+	if err := l.InsertE(4); err != nil {
+		t.Fatal(err)
+	}
+
+	l.Freeze()
+	if err := l.InsertE(5); !errors.Is(err, ErrFrozen) {
+		t.Fatalf("invalid insert into frozen list %v", err)
+	}
+	l.Unfreeze()
+
+	l.Close()
+	if err := l.InsertE(5); !errors.Is(err, ErrClosed) {
+		t.Fatalf("invalid insert into closed list %v", err)
+	}
+	if l.Len() != 3 {
+		t.Fatal("invalid length")
+	}
+
+	// Concurrent inserts never go over capacity.
+	c := NewConcurrentIntList(WithCapacity(100))
+	var (
+		wg       sync.WaitGroup
+		inserted int64
+	)
+	for i := 0; i < 1000; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			if c.InsertE(i) == nil {
+				atomic.AddInt64(&inserted, 1)
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+	if inserted != 100 || c.Len() != 100 || len(c.ToSlice()) != 100 {
+		t.Fatalf("invalid length %d with %d inserts", c.Len(), inserted)
+	}
+}
+
+func TestShardedCapacity(t *testing.T) {
+	// the capacity bounds the whole list, not each shard
+	s := NewShardedIntList(8, WithCapacity(100))
+	var (
+		wg       sync.WaitGroup
+		inserted int64
+	)
+	for i := 0; i < 1000; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			if s.Insert(i) {
+				atomic.AddInt64(&inserted, 1)
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+	if inserted != 100 || s.Len() != 100 || len(s.ToSlice()) != 100 {
+		t.Fatalf("invalid length %d with %d inserts", s.Len(), inserted)
+	}
+	// a delete from any shard makes room in every other
+	first := s.ToSlice()[0]
+	if !s.Delete(first) {
+		t.Fatalf("invalid delete of %d", first)
+	}
+	for i := 1000; ; i++ {
+		if s.ShardOf(i) != s.ShardOf(first) {
+			if !s.Insert(i) || s.Insert(i+1) || s.Len() != 100 {
+				t.Fatalf("invalid insert into another shard, len %d", s.Len())
+			}
+			break
+		}
+	}
+}
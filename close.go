@@ -0,0 +1,34 @@
+package collections
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// ErrClosed is returned by write operations on a closed list, and by a second Close.
+var ErrClosed = errors.New("collections: list is closed")
+
+// Close waits for the writes in flight and refuses every later one, including writes blocked by
+// a freeze when it is lifted: Insert and Delete return false and TryInsert and TryDelete return
+// ErrClosed. Reads keep working on the contents left at close time, except for the channels of
+// WatchMin, which are closed, and those of IterBuffered, which end early.
+func (intList *ConcurrentIntList) Close() error {
+	var err error
+	intList.quiesce(func() {
+		if intList.Closed() {
+			err = ErrClosed
+			return
+		}
+		atomic.StoreInt32(&intList.closed, 1)
+		close(intList.done)
+	})
+	if err == nil {
+		intList.closeWatches()
+	}
+	return err
+}
+
+// Closed reports whether Close has been called.
+func (intList *ConcurrentIntList) Closed() bool {
+	return atomic.LoadInt32(&intList.closed) == 1
+}
@@ -0,0 +1,50 @@
+package collections
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestClose(t *testing.T) {
+	l := NewConcurrentIntList()
+	l.Insert(1)
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !l.Closed() {
+		t.Fatal("invalid closed")
+	}
+	if err := l.Close(); !errors.Is(err, ErrClosed) {
+		t.Fatalf("invalid second close %v", err)
+	}
+	if l.Insert(2) || l.Delete(1) {
+		t.Fatal("write succeeds after close")
+	}
+	if ok, err := l.TryInsert(2); ok || !errors.Is(err, ErrClosed) {
+		t.Fatalf("invalid insert %v, %v", ok, err)
+	}
+	if ok, err := l.TryDelete(1); ok || !errors.Is(err, ErrClosed) {
+		t.Fatalf("invalid delete %v, %v", ok, err)
+	}
+	// Reads still work.
+	if !l.Contains(1) || l.Len() != 1 {
+		t.Fatal("invalid read after close")
+	}
+
+	// A writer blocked by a freeze is refused once the list is closed.
+	f := NewConcurrentIntList()
+	f.Freeze()
+	done := make(chan error)
+	go func() {
+		_, err := f.TryInsert(1)
+		done <- err
+	}()
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Unfreeze()
+	if err := <-done; !errors.Is(err, ErrClosed) {
+		t.Fatalf("invalid insert after close %v", err)
+	}
+}
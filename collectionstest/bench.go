@@ -0,0 +1,74 @@
+package collectionstest
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"testing"
+
+	collections "github.com/zsh1995/concurrency-in-go"
+)
+
+// benchKeys is the key space of the benchmarks, the list is prefilled with half of it so that
+// inserts and deletes succeed about as often as they fail.
+const benchKeys = 1 << 10
+
+// benchWorkloads are the share of Contains, in percent, of each workload, the other operations
+// are split evenly between Insert and Delete.
+var benchWorkloads = []struct {
+	name  string
+	reads int
+}{
+	{"read", 100},
+	{"mixed", 90},
+	{"write", 0},
+}
+
+var benchGoroutines = []int{1, 4, 16}
+
+// BenchmarkIntList runs read-heavy, mixed 90/10 and write-heavy workloads over lists built by
+// newList, each at several goroutine counts, so that IntList implementations can be compared on
+// the same load. Every sub-benchmark is named workload/goroutines=n and reports the standard
+// ns/op and allocs/op, one op being a single Insert, Delete or Contains.
+func BenchmarkIntList(b *testing.B, newList func() collections.IntList) {
+	for _, workload := range benchWorkloads {
+		for _, goroutines := range benchGoroutines {
+			b.Run(fmt.Sprintf("%s/goroutines=%d", workload.name, goroutines), func(b *testing.B) {
+				benchmarkWorkload(b, newList(), workload.reads, goroutines)
+			})
+		}
+	}
+}
+
+func benchmarkWorkload(b *testing.B, l collections.IntList, reads, goroutines int) {
+	for value := 0; value < benchKeys; value += 2 {
+		l.Insert(value)
+	}
+	seed := rand.Uint64()
+	b.ReportAllocs()
+	b.ResetTimer()
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		ops := b.N / goroutines
+		if g < b.N%goroutines {
+			ops++
+		}
+		wg.Add(1)
+		go func(g, ops int) {
+			defer wg.Done()
+			rnd := rand.New(rand.NewPCG(seed, uint64(g)))
+			for i := 0; i < ops; i++ {
+				value := rnd.IntN(benchKeys)
+				switch op := rnd.IntN(100); {
+				case op < reads:
+					l.Contains(value)
+				case op%2 == 0:
+					l.Insert(value)
+				default:
+					l.Delete(value)
+				}
+			}
+		}(g, ops)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,109 @@
+// Package collectionstest provides a conformance suite for collections.IntList implementations.
+package collectionstest
+
+import (
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"testing"
+
+	collections "github.com/zsh1995/concurrency-in-go"
+)
+
+const (
+	workers      = 8
+	opsPerWorker = 2000
+	// valuesPerWorker is kept small so that workers' values interleave and collide in the list.
+	valuesPerWorker = 64
+)
+
+// reference is a trivially correct sorted set.
+type reference struct {
+	mu     sync.Mutex
+	values []int
+}
+
+func (r *reference) insert(value int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i, found := slices.BinarySearch(r.values, value)
+	if found {
+		return false
+	}
+	r.values = slices.Insert(r.values, i, value)
+	return true
+}
+
+func (r *reference) delete(value int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i, found := slices.BinarySearch(r.values, value)
+	if !found {
+		return false
+	}
+	r.values = slices.Delete(r.values, i, i+1)
+	return true
+}
+
+func (r *reference) contains(value int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, found := slices.BinarySearch(r.values, value)
+	return found
+}
+
+// VerifyAgainstReference runs a randomized mix of Insert, Delete and Contains from several
+// goroutines against a list built by newList and against a reference sorted set, and fails t
+// on any disagreement. Each goroutine owns a disjoint set of interleaved values, so every result
+// is deterministic while the goroutines still modify neighbouring nodes concurrently.
+// Once they are done Len and Range, which must be ascending, are compared with the reference.
+// Run it with -race to also check the implementation for data races.
+func VerifyAgainstReference(t testing.TB, newList func() collections.IntList) {
+	t.Helper()
+	l, ref := newList(), &reference{}
+	seed := rand.Uint64()
+
+	var wg sync.WaitGroup
+	errs := make(chan string, workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			rnd := rand.New(rand.NewPCG(seed, uint64(w)))
+			for i := 0; i < opsPerWorker; i++ {
+				value := rnd.IntN(valuesPerWorker)*workers + w
+				var got, want bool
+				op := rnd.IntN(3)
+				switch op {
+				case 0:
+					got, want = l.Insert(value), ref.insert(value)
+				case 1:
+					got, want = l.Delete(value), ref.delete(value)
+				default:
+					got, want = l.Contains(value), ref.contains(value)
+				}
+				if got != want {
+					errs <- [...]string{"Insert", "Delete", "Contains"}[op]
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for op := range errs {
+		t.Fatalf("%s disagrees with the reference (seed %d)", op, seed)
+	}
+
+	if l.Len() != len(ref.values) {
+		t.Fatalf("Len is %d, expected %d (seed %d)", l.Len(), len(ref.values), seed)
+	}
+	var got []int
+	l.Range(func(value int) bool {
+		got = append(got, value)
+		return true
+	})
+	if !slices.Equal(got, ref.values) {
+		t.Fatalf("Range yields %v, expected %v (seed %d)", got, ref.values, seed)
+	}
+}
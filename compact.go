@@ -0,0 +1,93 @@
+package collections
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Compact walks the list once and unlinks every deleted node that is still linked, it returns
+// how many it reclaimed. Delete unlinks its node right after marking it, so a linked deleted
+// node is normally an in-flight Delete: Compact locks nodes like Delete does, current then pre,
+// so it waits for that Delete to finish, finds the node already gone and doesn't count it.
+// Whoever unlinks a node brings the size down, so Len drops by the returned count. Compact is a
+// write, it waits while the list is frozen and returns 0 if writes are refused.
+func (intList *ConcurrentIntList) Compact() int {
+	if intList.beginWrite(time.Time{}) != nil {
+		return 0
+	}
+	atomic.AddUint64(&intList.compactions, 1)
+	var reclaimed int
+	pre := intList.root
+	for current := pre.next(); current != nil; current = pre.next() {
+		if !current.marked() {
+			pre = current
+			continue
+		}
+		current.mutex.Lock()
+		pre.mutex.Lock()
+		if pre.marked() {
+			// pre has been deleted meanwhile, start over from the head
+			pre.mutex.Unlock()
+			current.mutex.Unlock()
+			pre = intList.root
+			continue
+		}
+		if pre.next() == current {
+			pre.updateNext(current.next())
+			intList.sizeDecr()
+			intList.versionIncr()
+			reclaimed++
+		}
+		pre.mutex.Unlock()
+		current.mutex.Unlock()
+	}
+	intList.endWrite()
+	for i := 0; i < reclaimed; i++ {
+		intList.bloomDeleted()
+	}
+	return reclaimed
+}
+
+// DeleteStatus is Delete also reporting whether a node holding value still lingers in the chain
+// once it returns: removed is the logical delete by this call, physicallyUnlinked is false if a
+// deleted node of value is still linked. A Delete unlinks its node before returning, so a node
+// lingers only while the Delete that marked it is in flight, or if it was never unlinked, and
+// Compact reclaims it. A call that removes value itself always reports it unlinked.
+func (intList *ConcurrentIntList) DeleteStatus(value int) (removed bool, physicallyUnlinked bool) {
+	value = intList.canon(value)
+	_, removed, _ = intList.removeUntil(value, true, time.Time{})
+	return removed, !intList.lingers(value)
+}
+
+// lingers reports whether a marked node holding value is still linked.
+func (intList *ConcurrentIntList) lingers(value int) bool {
+	for n := intList.walkStart(value).next(); n != nil && !intList.less(value, n.value); n = n.next() {
+		if n.value == value && n.marked() {
+			return true
+		}
+	}
+	return false
+}
+
+// SoftDelete deletes value and returns an undo restoring it, ok is false if value was absent or
+// the write was refused. value is absent from Contains and Range as soon as SoftDelete returns,
+// like after Delete. The node is unlinked right away rather than left marked in the chain,
+// where it would stall the writers around it, so undo reinserts value instead of unmarking it.
+// The window closes at the next Compact, which would have reclaimed a lingering node: undo
+// returns true if no Compact ran since SoftDelete and value was not inserted again meanwhile,
+// false otherwise. undo only succeeds once.
+func (intList *ConcurrentIntList) SoftDelete(value int) (undo func() bool, ok bool) {
+	value = intList.canon(value)
+	// read before the delete, a Compact running meanwhile closes the window
+	gen := atomic.LoadUint64(&intList.compactions)
+	if _, ok, _ = intList.removeUntil(value, true, time.Time{}); !ok {
+		return nil, false
+	}
+	var undone int32
+	return func() bool {
+		if !atomic.CompareAndSwapInt32(&undone, 0, 1) || atomic.LoadUint64(&intList.compactions) != gen {
+			return false
+		}
+		return intList.insert(value) == nil
+	}, true
+}
@@ -0,0 +1,130 @@
+package collections
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestCompact(t *testing.T) {
+	l := newListOf(1, 2, 3, 4, 5, 6)
+	if n := l.Compact(); n != 0 || l.Len() != 6 {
+		t.Fatalf("invalid compact of a clean list %d", n)
+	}
+
+	// Nodes marked by hand, as a Delete that never got to unlink them.
+	for n := l.root.next(); n != nil; n = n.next() {
+		if n.value%2 == 0 {
+			n.mark()
+		}
+	}
+	if n := l.Compact(); n != 3 || l.Len() != 3 || l.HealthCheck() != nil {
+		t.Fatalf("invalid compact expected %d, got %d", 3, n)
+	}
+	for n := l.root.next(); n != nil; n = n.next() {
+		if n.marked() {
+			t.Fatalf("%d still linked after compact", n.value)
+		}
+	}
+
+	// A Delete paused before unlinking keeps its node: Compact waits for it and doesn't
+	// count the node it unlinks.
+	marked, resume := make(chan struct{}), make(chan struct{})
+	testHookBeforeUnlink = func() {
+		marked <- struct{}{}
+		<-resume
+	}
+	defer func() { testHookBeforeUnlink = nil }()
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		l.Delete(3)
+		wg.Done()
+	}()
+	<-marked
+	go func() {
+		if n := l.Compact(); n != 0 {
+			panic("invalid compact of an in-flight delete")
+		}
+		wg.Done()
+	}()
+	close(resume)
+	wg.Wait()
+	if l.Len() != 2 || l.HealthCheck() != nil {
+		t.Fatal("invalid list after compact")
+	}
+}
+
+func TestDeleteStatus(t *testing.T) {
+	l := newListOf(1, 2, 3, 4, 5)
+	if removed, unlinked := l.DeleteStatus(1); !removed || !unlinked {
+		t.Fatalf("invalid status of a delete %v, %v", removed, unlinked)
+	}
+	if removed, unlinked := l.DeleteStatus(1); removed || !unlinked {
+		t.Fatalf("invalid status of an absent value %v, %v", removed, unlinked)
+	}
+
+	// A Delete paused before unlinking leaves its node linked.
+	marked, resume := make(chan struct{}), make(chan struct{})
+	var once sync.Once
+	testHookBeforeUnlink = func() {
+		once.Do(func() {
+			marked <- struct{}{}
+			<-resume
+		})
+	}
+	defer func() { testHookBeforeUnlink = nil }()
+	done := make(chan struct{})
+	go func() {
+		l.Delete(3)
+		close(done)
+	}()
+	<-marked
+	if removed, unlinked := l.DeleteStatus(3); removed || unlinked {
+		t.Fatalf("invalid status of an in-flight delete %v, %v", removed, unlinked)
+	}
+	close(resume)
+	<-done
+	if removed, unlinked := l.DeleteStatus(3); removed || !unlinked {
+		t.Fatalf("invalid status after the delete %v, %v", removed, unlinked)
+	}
+
+	// A node marked by hand lingers until Compact.
+	l.root.next().mark()
+	if removed, unlinked := l.DeleteStatus(2); removed || unlinked {
+		t.Fatalf("invalid status of a marked node %v, %v", removed, unlinked)
+	}
+	l.Compact()
+	if removed, unlinked := l.DeleteStatus(2); removed || !unlinked {
+		t.Fatalf("invalid status after compact %v, %v", removed, unlinked)
+	}
+}
+
+func TestSoftDelete(t *testing.T) {
+	l := newListOf(1, 2, 3)
+	undo, ok := l.SoftDelete(2)
+	if !ok || l.Contains(2) || l.Len() != 2 {
+		t.Fatalf("invalid soft delete %v, %v", ok, l.ToSlice())
+	}
+	if _, ok := l.SoftDelete(2); ok {
+		t.Fatal("invalid soft delete of an absent value")
+	}
+	if !undo() || !l.Contains(2) || l.Len() != 3 || l.HealthCheck() != nil {
+		t.Fatalf("invalid undo, got %v", l.ToSlice())
+	}
+	if undo() {
+		t.Fatal("invalid second undo")
+	}
+
+	// Compact closes the window.
+	undo, _ = l.SoftDelete(3)
+	l.Compact()
+	if undo() || l.Contains(3) {
+		t.Fatal("invalid undo after compact")
+	}
+
+	// A value inserted again meanwhile is not undone twice.
+	undo, _ = l.SoftDelete(1)
+	if !l.Insert(1) || undo() || l.Len() != 2 {
+		t.Fatalf("invalid undo of a reinserted value, got %v", l.ToSlice())
+	}
+}
@@ -0,0 +1,117 @@
+package collections
+
+import "slices"
+
+// ascendingSnapshot returns the values of the list in ascending order, whatever its own order.
+func (intList *ConcurrentIntList) ascendingSnapshot() []int {
+	values := intList.ToSlice()
+	slices.Sort(values)
+	return values
+}
+
+// EqualsWithin reports whether the values of both lists can be paired one to one so that the
+// values of each pair are at most tolerance apart, a negative tolerance is taken as 0. Both lists
+// are snapshotted and their values matched greedily in ascending order, the smallest unmatched
+// value of one list with the smallest unmatched value of the other, which finds a pairing
+// whenever one exists. In particular lists of different lengths are never equal.
+func (intList *ConcurrentIntList) EqualsWithin(other *ConcurrentIntList, tolerance int) bool {
+	a, b := intList.ascendingSnapshot(), other.ascendingSnapshot()
+	if len(a) != len(b) {
+		return false
+	}
+	tolerance = max(tolerance, 0)
+	for i := range a {
+		lo, hi := min(a[i], b[i]), max(a[i], b[i])
+		// the difference of two ints always fits in an uint64
+		if uint64(hi)-uint64(lo) > uint64(tolerance) {
+			return false
+		}
+	}
+	return true
+}
+
+// FirstDifference returns the first value, in the list's order, that is in only one of the list
+// and other, and inThisOnly tells which one holds it. found is false if both hold the same values.
+// Both lists are snapshotted and merged in a single walk.
+func (intList *ConcurrentIntList) FirstDifference(other *ConcurrentIntList) (value int, inThisOnly bool, found bool) {
+	intList.mergeGroups(intList.ToSlice(), intList.snapshotOf(other), func(ga, gb []int) bool {
+		if i := slices.IndexFunc(ga, func(v int) bool { return !slices.Contains(gb, v) }); i >= 0 {
+			value, inThisOnly, found = ga[i], true, true
+		} else if i := slices.IndexFunc(gb, func(v int) bool { return !slices.Contains(ga, v) }); i >= 0 {
+			value, inThisOnly, found = gb[i], false, true
+		}
+		return !found
+	})
+	return value, inThisOnly, found
+}
+
+// MergeRange walks a and b together and calls f for every value of both in ascending order,
+// fromA telling which list it comes from, stopping when f returns false. A value in both lists
+// is passed twice, first from a then from b. Both lists are walked as with Range and never
+// copied, their own order must be ascending.
+func MergeRange(a, b *ConcurrentIntList, f func(value int, fromA bool) bool) {
+	na, nb := nextLive(a.root.next()), nextLive(b.root.next())
+	for na != nil || nb != nil {
+		if na != nil && (nb == nil || na.value <= nb.value) {
+			if !f(na.value, true) {
+				return
+			}
+			na = nextLive(na.next())
+			continue
+		}
+		if !f(nb.value, false) {
+			return
+		}
+		nb = nextLive(nb.next())
+	}
+}
+
+// FilterPresent returns the values of values present in the list, deduplicated and in the
+// list's order. values is sorted, if it is not already, and merged with the list in a single
+// walk, each value found counts as present when the walk passes it.
+func (intList *ConcurrentIntList) FilterPresent(values []int) []int {
+	query := make([]int, len(values))
+	for i, value := range values {
+		query[i] = intList.canon(value)
+	}
+	if !slices.IsSortedFunc(query, intList.compare) {
+		slices.SortFunc(query, intList.compare)
+	}
+	present := make([]int, 0, len(query))
+	if len(query) == 0 {
+		return present
+	}
+	n := nextLive(intList.walkStart(query[0]).next())
+	// a group of tied values is matched against the whole group of the list
+	for len(query) > 0 && n != nil {
+		group := query[:intList.groupLen(query)]
+		query = query[len(group):]
+		for n != nil && intList.less(n.value, group[0]) {
+			n = nextLive(n.next())
+		}
+		for m := n; m != nil && !intList.less(group[0], m.value); m = nextLive(m.next()) {
+			if slices.Contains(group, m.value) {
+				present = append(present, m.value)
+			}
+		}
+	}
+	return present
+}
+
+// SymmetricDifference returns a new list, ordered like a, of the values present in exactly one
+// of a and b. Both lists are snapshotted first and the snapshots merged in a single walk, in
+// O(n+m).
+func SymmetricDifference(a, b *ConcurrentIntList) *ConcurrentIntList {
+	x, y := a.ToSlice(), a.snapshotOf(b)
+	values := make([]int, 0, len(x)+len(y))
+	a.mergeGroups(x, y, func(gx, gy []int) bool {
+		values = appendMissing(values, gx, gy)
+		values = appendMissing(values, gy, gx)
+		return true
+	})
+	diff := a.newEmpty()
+	// a tie between the two lists is a single element for EqualReject and EqualReplace
+	values = a.dedupe(values)
+	diff.installChain(values)
+	return diff
+}
@@ -0,0 +1,170 @@
+package collections
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func newListOf(values ...int) *ConcurrentIntList {
+	l := NewConcurrentIntList()
+	for _, value := range values {
+		l.Insert(value)
+	}
+	return l
+}
+
+func TestEqualsWithin(t *testing.T) {
+	a := newListOf(1, 5, 10)
+	if !a.EqualsWithin(newListOf(1, 5, 10), 0) || !a.EqualsWithin(a, 0) {
+		t.Fatal("invalid exact match")
+	}
+	if !a.EqualsWithin(newListOf(2, 4, 12), 2) || a.EqualsWithin(newListOf(2, 4, 12), 1) {
+		t.Fatal("invalid match within tolerance")
+	}
+	// 5 has no match within 1 in the other list
+	if a.EqualsWithin(newListOf(0, 8, 11), 1) {
+		t.Fatal("invalid match of a value without a match")
+	}
+	// greedy one to one: 1 and 2 can't both pair with 2
+	if newListOf(1, 2).EqualsWithin(newListOf(2, 10), 1) {
+		t.Fatal("invalid match reusing a value")
+	}
+	if a.EqualsWithin(newListOf(1, 5), 100) || !newListOf().EqualsWithin(newListOf(), 0) {
+		t.Fatal("invalid match of different lengths")
+	}
+	d := NewDescendingIntList()
+	for _, value := range []int{11, 6, 0} {
+		d.Insert(value)
+	}
+	if !a.EqualsWithin(d, 1) {
+		t.Fatal("invalid match with a descending list")
+	}
+	if newListOf(math.MinInt).EqualsWithin(newListOf(math.MaxInt), math.MaxInt) {
+		t.Fatal("invalid match of opposite extremes")
+	}
+	if !newListOf(0).EqualsWithin(newListOf(math.MaxInt), math.MaxInt) {
+		t.Fatal("invalid match at the largest tolerance")
+	}
+}
+
+func TestFirstDifference(t *testing.T) {
+	check := func(a, b *ConcurrentIntList, value int, inThisOnly bool) {
+		t.Helper()
+		got, gotThis, found := a.FirstDifference(b)
+		if !found || got != value || gotThis != inThisOnly {
+			t.Fatalf("invalid first difference expected %d, %v, got %d, %v, %v", value, inThisOnly, got, gotThis, found)
+		}
+	}
+	check(newListOf(0, 1, 2), newListOf(1, 2), 0, true)
+	check(newListOf(1, 2), newListOf(0, 1, 2), 0, false)
+	check(newListOf(1, 2, 4, 5), newListOf(1, 2, 3, 5), 3, false)
+	check(newListOf(1, 2, 3), newListOf(1, 2, 4), 3, true)
+	check(newListOf(1, 2, 3), newListOf(1, 2), 3, true)
+	check(newListOf(1, 2), newListOf(1, 2, 3, 4), 3, false)
+	check(newListOf(), newListOf(7), 7, false)
+
+	d := NewDescendingIntList()
+	d.Insert(1)
+	d.Insert(3)
+	check(d, newListOf(1, 2, 3), 2, false)
+
+	if _, _, found := newListOf(1, 2, 3).FirstDifference(newListOf(3, 2, 1)); found {
+		t.Fatal("invalid difference between equal lists")
+	}
+	if _, _, found := newListOf().FirstDifference(newListOf()); found {
+		t.Fatal("invalid difference between empty lists")
+	}
+}
+
+func TestMergeRange(t *testing.T) {
+	a, b := newListOf(1, 4, 5, 9), newListOf(2, 4, 10)
+	var (
+		values []int
+		origin []bool
+	)
+	MergeRange(a, b, func(value int, fromA bool) bool {
+		values = append(values, value)
+		origin = append(origin, fromA)
+		return true
+	})
+	if !slices.Equal(values, []int{1, 2, 4, 4, 5, 9, 10}) {
+		t.Fatalf("invalid merged order %v", values)
+	}
+	if !slices.Equal(origin, []bool{true, false, true, false, true, true, false}) {
+		t.Fatalf("invalid origins %v", origin)
+	}
+
+	values = values[:0]
+	MergeRange(a, b, func(value int, _ bool) bool {
+		values = append(values, value)
+		return len(values) < 3
+	})
+	if !slices.Equal(values, []int{1, 2, 4}) {
+		t.Fatalf("invalid stopped merge %v", values)
+	}
+
+	values = values[:0]
+	MergeRange(NewConcurrentIntList(), b, func(value int, fromA bool) bool {
+		if fromA {
+			t.Fatal("invalid origin from an empty list")
+		}
+		values = append(values, value)
+		return true
+	})
+	if !slices.Equal(values, b.ToSlice()) {
+		t.Fatalf("invalid merge with an empty list %v", values)
+	}
+}
+
+func TestFilterPresent(t *testing.T) {
+	l := newListOf(1, 3, 5, 7, 9)
+	for _, c := range []struct {
+		query, want []int
+	}{
+		{[]int{9, 1, 5, 3, 7}, []int{1, 3, 5, 7, 9}},
+		{[]int{0, 2, 4, 10}, []int{}},
+		{[]int{7, 2, 7, 3, 3, 100, -1}, []int{3, 7}},
+		{nil, []int{}},
+	} {
+		if got := l.FilterPresent(c.query); !slices.Equal(got, c.want) {
+			t.Fatalf("invalid filter of %v expected %v, got %v", c.query, c.want, got)
+		}
+	}
+
+	d := NewDescendingIntList()
+	d.InsertSortedDesc([]int{1, 2, 3, 4})
+	if got := d.FilterPresent([]int{1, 4, 5, 3}); !slices.Equal(got, []int{4, 3, 1}) {
+		t.Fatalf("invalid filter of a descending list %v", got)
+	}
+}
+
+func TestSymmetricDifference(t *testing.T) {
+	for _, c := range []struct {
+		a, b, want []int
+	}{
+		{[]int{1, 3, 5}, []int{2, 4}, []int{1, 2, 3, 4, 5}},
+		{[]int{1, 2, 3}, []int{1, 2, 3}, []int{}},
+		{[]int{1, 2, 3, 4}, []int{3, 4, 5, 6}, []int{1, 2, 5, 6}},
+		{nil, []int{7}, []int{7}},
+	} {
+		a, b := newListOf(c.a...), newListOf(c.b...)
+		diff := SymmetricDifference(a, b)
+		if got := diff.ToSlice(); !slices.Equal(got, c.want) || diff.Len() != len(c.want) {
+			t.Fatalf("invalid difference of %v and %v, got %v", c.a, c.b, got)
+		}
+		if got := SymmetricDifference(b, a).ToSlice(); !slices.Equal(got, c.want) {
+			t.Fatalf("invalid difference of %v and %v, got %v", c.b, c.a, got)
+		}
+		if a.Len() != len(c.a) || b.Len() != len(c.b) {
+			t.Fatal("invalid source after difference")
+		}
+	}
+
+	// the result follows the order of a
+	d := NewDescendingIntList()
+	d.InsertSortedDesc([]int{1, 2, 3})
+	if got := SymmetricDifference(d, newListOf(2, 4)).ToSlice(); !slices.Equal(got, []int{4, 3, 1}) {
+		t.Fatalf("invalid descending difference %v", got)
+	}
+}
@@ -0,0 +1,46 @@
+package collections_test
+
+import (
+	"testing"
+
+	collections "github.com/zsh1995/concurrency-in-go"
+	"github.com/zsh1995/concurrency-in-go/collectionstest"
+)
+
+func TestConformance(t *testing.T) {
+	collectionstest.VerifyAgainstReference(t, func() collections.IntList {
+		return collections.NewConcurrentIntList()
+	})
+	collectionstest.VerifyAgainstReference(t, func() collections.IntList {
+		return collections.NewConcurrentRangeSet()
+	})
+	collectionstest.VerifyAgainstReference(t, func() collections.IntList {
+		return collections.NewShardedIntList(4)
+	})
+	collectionstest.VerifyAgainstReference(t, func() collections.IntList {
+		return collections.NewAdaptiveIntList()
+	})
+}
+
+func BenchmarkConformance(b *testing.B) {
+	b.Run("list", func(b *testing.B) {
+		collectionstest.BenchmarkIntList(b, func() collections.IntList {
+			return collections.NewConcurrentIntList()
+		})
+	})
+	b.Run("rangeset", func(b *testing.B) {
+		collectionstest.BenchmarkIntList(b, func() collections.IntList {
+			return collections.NewConcurrentRangeSet()
+		})
+	})
+	b.Run("sharded", func(b *testing.B) {
+		collectionstest.BenchmarkIntList(b, func() collections.IntList {
+			return collections.NewShardedIntList(0)
+		})
+	})
+	b.Run("adaptive", func(b *testing.B) {
+		collectionstest.BenchmarkIntList(b, func() collections.IntList {
+			return collections.NewAdaptiveIntList()
+		})
+	})
+}
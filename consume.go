@@ -0,0 +1,86 @@
+package collections
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// ConsumeRange deletes every value from lo to hi included and returns them in the list's order,
+// in a single write: the predecessor of lo and every node up to the last value not after hi are
+// locked together, marked and spliced out at once, so of concurrent consumers of overlapping
+// ranges each value goes to exactly one. Nodes are locked from the last to the first, the
+// successor before its predecessor like Delete, so ConsumeRange never dead locks with the other
+// writes. Readers may see part of the range gone while it is in flight, like for any write.
+// It returns nil if the write is refused.
+func (intList *ConcurrentIntList) ConsumeRange(lo, hi int) []int {
+	if intList.beginWrite(time.Time{}) != nil {
+		return nil
+	}
+	span := intList.consumeRange(lo, hi)
+	intList.endWrite()
+	values := make([]int, len(span))
+	for i, n := range span {
+		values[i] = n.value
+		intList.bloomDeleted()
+	}
+	return values
+}
+
+// consumeRange unlinks the nodes of ConsumeRange and returns them.
+func (intList *ConcurrentIntList) consumeRange(lo, hi int) []*intNode {
+	var span []*intNode
+	retries := -1
+	// observed once, with the final count, when the operation returns
+	defer func() { intList.observeRetries(retries) }()
+start:
+	retries++
+	// step1: find the predecessor of lo and the nodes up to hi
+	pre := intList.walkStart(lo)
+	current := pre.next()
+	for current != nil && (current.marked() || intList.less(current.value, lo)) {
+		pre = current
+		current = pre.next()
+	}
+	span = span[:0]
+	for ; current != nil && !intList.less(hi, current.value); current = current.next() {
+		span = append(span, current)
+	}
+	if len(span) == 0 {
+		return nil
+	}
+	// step2: lock from the last node back to pre
+	for i := len(span) - 1; i >= 0; i-- {
+		span[i].mutex.Lock()
+	}
+	pre.mutex.Lock()
+	unlock := func() {
+		pre.mutex.Unlock()
+		for _, n := range span {
+			n.mutex.Unlock()
+		}
+	}
+	// step3: check that the locked nodes are still the whole range, linked one after the other
+	if pre.marked() || pre.next() != span[0] {
+		unlock()
+		goto start
+	}
+	for i, n := range span {
+		if n.marked() || (i > 0 && span[i-1].next() != n) {
+			unlock()
+			goto start
+		}
+	}
+	if succ := span[len(span)-1].next(); succ != nil && !intList.less(hi, succ.value) {
+		unlock()
+		goto start
+	}
+	// step4: mark and splice out
+	for _, n := range span {
+		n.mark()
+	}
+	pre.updateNext(span[len(span)-1].next())
+	atomic.AddInt64(&intList.size, -int64(len(span)))
+	intList.versionIncr()
+	unlock()
+	return span
+}
@@ -0,0 +1,71 @@
+package collections
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestConsumeRange(t *testing.T) {
+	l := newListOf(1, 3, 5, 7, 9)
+	if got := l.ConsumeRange(2, 7); !slices.Equal(got, []int{3, 5, 7}) {
+		t.Fatalf("invalid consumed range %v", got)
+	}
+	if got := l.ToSlice(); !slices.Equal(got, []int{1, 9}) || l.Len() != 2 || l.HealthCheck() != nil {
+		t.Fatalf("invalid list after consume %v", got)
+	}
+	if got := l.ConsumeRange(2, 8); len(got) != 0 {
+		t.Fatalf("invalid consume of an empty range %v", got)
+	}
+	if got := l.ConsumeRange(1, 1); !slices.Equal(got, []int{1}) || l.Contains(1) {
+		t.Fatalf("invalid consume of a single value %v", got)
+	}
+
+	// overlapping windows consumed concurrently, with writers around and inside them
+	l = NewConcurrentIntList()
+	for i := 0; i < 2000; i++ {
+		l.Insert(i)
+	}
+	var (
+		mu       sync.Mutex
+		consumed []int
+		wg       sync.WaitGroup
+	)
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for lo := g * 25; lo < 2000; lo += 100 {
+				got := l.ConsumeRange(lo, lo+150)
+				if !slices.IsSorted(got) {
+					panic("unsorted consumed range")
+				}
+				mu.Lock()
+				consumed = append(consumed, got...)
+				mu.Unlock()
+				l.Insert(lo + 3000)
+				l.Delete(lo + 3000)
+			}
+		}()
+	}
+	// deletes inside the windows lock nodes in the same order
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 1999; i >= 0; i -= 7 {
+			if l.Delete(i) {
+				mu.Lock()
+				consumed = append(consumed, i)
+				mu.Unlock()
+			}
+		}
+	}()
+	wg.Wait()
+	slices.Sort(consumed)
+	if len(consumed) != 2000 || len(slices.Compact(consumed)) != 2000 {
+		t.Fatalf("invalid consumed values, %d of 2000", len(consumed))
+	}
+	if l.Len() != 0 || l.HealthCheck() != nil {
+		t.Fatalf("invalid list after concurrent consumes, %d values", l.Len())
+	}
+}
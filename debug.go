@@ -0,0 +1,55 @@
+//go:build collectionsdebug
+
+package collections
+
+import "fmt"
+
+// debugChecks enables invariant assertions, build with -tags collectionsdebug.
+const debugChecks = true
+
+// assertInserted panics if n, just linked after pre, is not strictly ordered between its
+// neighbors, or if the comparator is not anti-symmetric on them. It must be called with pre locked.
+func (intList *ConcurrentIntList) assertInserted(pre, n *intNode) {
+	if pre != intList.root {
+		intList.assertAntiSymmetric(pre.value, n.value)
+		if !intList.before(pre.value, n.value) {
+			panic(fmt.Sprintf("collections: inserted %d after %d", n.value, pre.value))
+		}
+	}
+	if next := n.next(); next != nil {
+		intList.assertAntiSymmetric(n.value, next.value)
+		if !intList.before(n.value, next.value) {
+			panic(fmt.Sprintf("collections: inserted %d before %d", n.value, next.value))
+		}
+	}
+}
+
+// assertAntiSymmetric samples the comparator on two neighbors, a subtraction based one reports
+// both a < b and b < a once a-b overflows.
+func (intList *ConcurrentIntList) assertAntiSymmetric(a, b int) {
+	if intList.less(a, b) && intList.less(b, a) {
+		panic(fmt.Sprintf("collections: comparator is not anti-symmetric on %d and %d", a, b))
+	}
+}
+
+// assertNoCycle moves slow, the tortoise, one node for every two steps of n, the i-th node of a
+// walk, and panics once n catches up with it on a cycle. A concurrent unlink can let slow skip
+// ahead of n, so a meeting is only reported if walking on from n leads back to it, which can't
+// happen on an acyclic list, otherwise slow restarts from the head.
+func (intList *ConcurrentIntList) assertNoCycle(slow, n *intNode, i int) *intNode {
+	if slow == nil {
+		slow = intList.root
+	}
+	if i%2 == 1 {
+		slow = slow.next()
+	}
+	if slow != n {
+		return slow
+	}
+	for m := n.next(); m != nil; m = m.next() {
+		if m == n {
+			panic(fmt.Sprintf("collections: cycle through %d", n.value))
+		}
+	}
+	return nil
+}
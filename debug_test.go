@@ -0,0 +1,95 @@
+//go:build collectionsdebug
+
+package collections
+
+import (
+	"math"
+	"sync"
+	"testing"
+)
+
+func TestAssertInserted(t *testing.T) {
+	l := NewConcurrentIntList()
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			for i := 0; i < 1000; i++ {
+				if fastrandn(2) == 0 {
+					l.Insert(int(fastrandn(32)))
+				} else {
+					l.Delete(int(fastrandn(32)))
+				}
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	// Link a duplicate by hand, as a buggy insert path would.
+	l = NewConcurrentIntList()
+	l.Insert(1)
+	l.Insert(2)
+	first := l.root.next()
+	duplicate := newIntNode(1)
+	duplicate.updateNext(first.next())
+	first.updateNext(duplicate)
+	defer func() {
+		if recover() == nil {
+			t.Fatal("duplicate insert not detected")
+		}
+	}()
+	l.assertInserted(first, duplicate)
+}
+
+func TestAssertAntiSymmetric(t *testing.T) {
+	// 0-math.MinInt and math.MinInt-0 both overflow to math.MinInt
+	l := NewConcurrentIntList(WithComparator(func(a, b int) bool { return a-b < 0 }))
+	l.Insert(0)
+	defer func() {
+		if recover() == nil {
+			t.Fatal("comparator overflow not detected")
+		}
+	}()
+	l.Insert(math.MinInt)
+}
+
+func TestAssertNoCycle(t *testing.T) {
+	// Concurrent unlinks must not be taken for a cycle.
+	l := NewConcurrentIntList()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			for j := 0; j < 2000; j++ {
+				switch {
+				case i%2 == 0:
+					l.Insert(int(fastrandn(256)))
+				case j%2 == 0:
+					l.Delete(int(fastrandn(256)))
+				default:
+					l.Range(func(int) bool { return true })
+				}
+			}
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+
+	// Link the last node back to the first by hand.
+	l = NewConcurrentIntList()
+	for i := 0; i < 5; i++ {
+		l.Insert(i)
+	}
+	last := l.root.next()
+	for last.next() != nil {
+		last = last.next()
+	}
+	last.updateNext(l.root.next().next())
+	defer func() {
+		if recover() == nil {
+			t.Fatal("cycle not detected")
+		}
+	}()
+	l.Range(func(int) bool { return true })
+}
@@ -0,0 +1,44 @@
+package collections
+
+import "slices"
+
+// newEmpty returns an empty list with the same ordering and equal policy as intList.
+func (intList *ConcurrentIntList) newEmpty() *ConcurrentIntList {
+	empty, _ := newConcurrentIntList(intList.less, nil)
+	empty.equalPolicy, empty.hasEqualPolicy = intList.equalPolicy, intList.hasEqualPolicy
+	return empty
+}
+
+// PartitionBy snapshots the list and distributes every value into a new list keyed by
+// key(value), each bucket keeps the ordering of intList. The source list is not modified.
+// It allocates a node per value plus the snapshot, so the memory cost is about twice the list.
+func (intList *ConcurrentIntList) PartitionBy(key func(value int) int) map[int]*ConcurrentIntList {
+	buckets := make(map[int][]int)
+	for _, value := range intList.ToSlice() {
+		k := key(value)
+		buckets[k] = append(buckets[k], value)
+	}
+	partitions := make(map[int]*ConcurrentIntList, len(buckets))
+	for k, values := range buckets {
+		partitions[k] = intList.newEmpty()
+		partitions[k].InsertSorted(values)
+	}
+	return partitions
+}
+
+// MapToList snapshots the list and returns a new list, with the same ordering, holding f of
+// every value. f need not keep the order, the results are sorted and deduplicated before the
+// new list is built in one pass. The source list is not modified.
+func (intList *ConcurrentIntList) MapToList(f func(value int) int) *ConcurrentIntList {
+	values := intList.ToSlice()
+	for i, value := range values {
+		values[i] = f(value)
+	}
+	if !slices.IsSortedFunc(values, intList.compare) {
+		slices.SortStableFunc(values, intList.compare)
+	}
+	values = intList.dedupe(values)
+	mapped := intList.newEmpty()
+	mapped.installChain(values)
+	return mapped
+}
@@ -0,0 +1,67 @@
+package collections
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPartitionBy(t *testing.T) {
+	l := NewConcurrentIntList()
+	for i := 0; i < 100; i++ {
+		l.Insert(int(fastrandn(1000)))
+	}
+
+	partitions := l.PartitionBy(func(value int) int {
+		return value % 3
+	})
+	if len(partitions) > 3 {
+		t.Fatal("invalid partition count")
+	}
+	var total int
+	for k, p := range partitions {
+		if !p.IsSorted() {
+			t.Fatal("invalid partition order")
+		}
+		p.Range(func(value int) bool {
+			if value%3 != k || !l.Contains(value) {
+				t.Fatalf("invalid value %d in partition %d", value, k)
+			}
+			return true
+		})
+		total += p.Len()
+	}
+	if total != l.Len() {
+		t.Fatalf("invalid union length expected %d, got %d", l.Len(), total)
+	}
+
+	if len(NewConcurrentIntList().PartitionBy(func(int) int { return 0 })) != 0 {
+		t.Fatal("invalid partition of empty list")
+	}
+}
+
+func TestMapToList(t *testing.T) {
+	l := newListOf(-3, -1, 0, 2, 5)
+
+	doubled := l.MapToList(func(value int) int { return value * 2 })
+	if got := doubled.ToSlice(); !slices.Equal(got, []int{-6, -2, 0, 4, 10}) || doubled.Len() != 5 {
+		t.Fatalf("invalid monotonic map %v", got)
+	}
+	squared := l.MapToList(func(value int) int { return value * value })
+	if got := squared.ToSlice(); !slices.Equal(got, []int{0, 1, 4, 9, 25}) || squared.Len() != 5 {
+		t.Fatalf("invalid non-monotonic map %v", got)
+	}
+	abs := newListOf(-2, -1, 1, 2).MapToList(func(value int) int { return max(value, -value) })
+	if got := abs.ToSlice(); !slices.Equal(got, []int{1, 2}) || abs.Len() != 2 {
+		t.Fatalf("invalid deduplication %v", got)
+	}
+	if got := l.ToSlice(); !slices.Equal(got, []int{-3, -1, 0, 2, 5}) {
+		t.Fatalf("invalid source after map %v", got)
+	}
+
+	// the new list keeps the ordering
+	d := NewDescendingIntList()
+	d.InsertSortedDesc([]int{1, 2, 3})
+	if got := d.MapToList(func(value int) int { return -value }).ToSlice(); !slices.Equal(got, []int{-1, -2, -3}) {
+		t.Fatalf("invalid descending map %v", got)
+	}
+}
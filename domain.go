@@ -0,0 +1,24 @@
+package collections
+
+import "errors"
+
+// ErrOutOfDomain is returned when inserting a value outside the range set by WithDomain.
+var ErrOutOfDomain = errors.New("collections: value out of domain")
+
+// WithDomain restricts the list to values in [min, max], Insert returns false and
+// InsertChecked returns ErrOutOfDomain for any other value.
+func WithDomain(min, max int) Option {
+	return func(intList *ConcurrentIntList) {
+		intList.hasDomain = true
+		intList.domainMin, intList.domainMax = min, max
+	}
+}
+
+func (intList *ConcurrentIntList) inDomain(value int) bool {
+	return !intList.hasDomain || (value >= intList.domainMin && value <= intList.domainMax)
+}
+
+// InsertChecked is Insert returning the reason a value was not inserted, it is the same as InsertE.
+func (intList *ConcurrentIntList) InsertChecked(value int) error {
+	return intList.insert(value)
+}
@@ -0,0 +1,43 @@
+package collections
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestWithDomain(t *testing.T) {
+	l := NewConcurrentIntList(WithDomain(-10, 10))
+
+	for _, v := range []int{-10, 0, 10} {
+		if err := l.InsertChecked(v); err != nil {
+			t.Fatalf("invalid insert of %d: %v", v, err)
+		}
+	}
+	if err := l.InsertChecked(0); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("invalid duplicate insert %v", err)
+	}
+	for _, v := range []int{-11, 11, 1000} {
+		if err := l.InsertChecked(v); !errors.Is(err, ErrOutOfDomain) {
+			t.Fatalf("invalid insert of %d: %v", v, err)
+		}
+		if l.Insert(v) {
+			t.Fatalf("out of domain value %d inserted", v)
+		}
+		if ok, err := l.TryInsert(v); ok || !errors.Is(err, ErrOutOfDomain) {
+			t.Fatalf("invalid insert of %d: %v, %v", v, ok, err)
+		}
+	}
+	if n := l.InsertSorted([]int{-20, 5, 20}); n != 1 {
+		t.Fatalf("invalid insert count %d", n)
+	}
+	if l.Len() != 4 || l.Contains(11) || !l.Contains(5) {
+		t.Fatal("invalid contents")
+	}
+
+	// Without a domain every value is accepted.
+	u := NewConcurrentIntList()
+	if err := u.InsertChecked(math.MaxInt); err != nil {
+		t.Fatal(err)
+	}
+}
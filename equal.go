@@ -0,0 +1,176 @@
+package collections
+
+import "slices"
+
+// EqualPolicy decides what Insert does with a value the comparator ties with a different
+// present value, a value neither ordered before nor after it.
+type EqualPolicy int
+
+const (
+	// EqualAllow keeps tied values side by side, a new value is placed after those it ties
+	// with, so a group of ties is kept in insertion order.
+	EqualAllow EqualPolicy = iota
+	// EqualReject refuses a value tied with a present one, Insert returns false and InsertE
+	// returns ErrDuplicate.
+	EqualReject
+	// EqualReplace replaces the present tied value by the new one in a single write, Insert
+	// returns true and Len is unchanged.
+	EqualReplace
+)
+
+// WithEqualPolicy lets the comparator of WithComparator be a preorder, tying distinct values,
+// and sets what Insert does on a tie. Values are still compared with == for presence, so the
+// list never holds the same int twice: EqualAllow makes it a multiset of tied values, EqualReject
+// a set of the comparator's classes and EqualReplace an upsert of them. Lookups and deletes
+// walk through the group of ties of a value. Without it the comparator must not tie distinct
+// values.
+func WithEqualPolicy(policy EqualPolicy) Option {
+	return func(intList *ConcurrentIntList) {
+		intList.equalPolicy, intList.hasEqualPolicy = policy, true
+	}
+}
+
+// tied reports whether a and b are distinct values the comparator ties, which only happens
+// with WithEqualPolicy.
+func (intList *ConcurrentIntList) tied(a, b int) bool {
+	return intList.hasEqualPolicy && a != b && !intList.less(a, b) && !intList.less(b, a)
+}
+
+// before reports whether a may be placed before b, ordered before it or tied with it, so a
+// lookup of b walks past a.
+func (intList *ConcurrentIntList) before(a, b int) bool {
+	return intList.less(a, b) || intList.tied(a, b)
+}
+
+// replaceAfter replaces current, which follows pre and ties with value, by a new node holding
+// value, locking like a Delete. ok is false if either node changed meanwhile and the insert
+// must walk again.
+func (intList *ConcurrentIntList) replaceAfter(pre, current *intNode, value int, nb *neighbors) (*intNode, bool) {
+	current.mutex.Lock()
+	if current.marked() {
+		current.mutex.Unlock()
+		return nil, false
+	}
+	pre.mutex.Lock()
+	if pre.next() != current || pre.marked() {
+		pre.mutex.Unlock()
+		current.mutex.Unlock()
+		return nil, false
+	}
+	if bloom := intList.bloomFilter(); bloom != nil {
+		bloom.add(value)
+	}
+	newNode := newIntNode(value)
+	newNode.updateNext(current.next())
+	// marked first, a reader standing on current must not report it once value is linked
+	current.mark()
+	pre.updateNext(newNode)
+	intList.versionIncr()
+	pre.mutex.Unlock()
+	current.mutex.Unlock()
+	if nb != nil {
+		intList.fillNeighbors(nb, pre, newNode)
+	}
+	return newNode, true
+}
+
+// DistinctLen returns the number of distinct values in the comparator's sense, counting each
+// group of tied values once, by a single walk counting the group boundaries. Len counts every
+// value, so both only differ for a list with WithEqualPolicy(EqualAllow). Like Range the walk
+// runs concurrently with the writes.
+func (intList *ConcurrentIntList) DistinctLen() int {
+	var (
+		count  int
+		pre    int
+		hasPre bool
+	)
+	intList.Range(func(value int) bool {
+		if !hasPre || !intList.tied(pre, value) {
+			count++
+		}
+		pre, hasPre = value, true
+		return true
+	})
+	return count
+}
+
+// equalOrTied returns the present value equal to value or tied with it, the first one found.
+func (intList *ConcurrentIntList) equalOrTied(value int) (int, bool) {
+	next := intList.walkStart(value).next()
+	for next != nil && (next.marked() || intList.less(next.value, value)) {
+		next = next.next()
+	}
+	if next == nil || (next.value != value && !intList.tied(next.value, value)) {
+		return 0, false
+	}
+	return next.value, true
+}
+
+// groupLen returns the number of values at the start of values, which follow the list's order,
+// tied with the first one, itself included. It is 1 without WithEqualPolicy.
+func (intList *ConcurrentIntList) groupLen(values []int) int {
+	n := 1
+	for n < len(values) && !intList.less(values[0], values[n]) {
+		n++
+	}
+	return n
+}
+
+// mergeGroups walks a and b, which follow the list's order, together one group of tied values
+// at a time and calls f with the groups of a and b at the same position, a nil group when only
+// one of them has values there, stopping when f returns false. Presence within a group must be
+// checked with ==, the order of tied values differs between lists.
+func (intList *ConcurrentIntList) mergeGroups(a, b []int, f func(ga, gb []int) bool) {
+	for len(a) > 0 || len(b) > 0 {
+		var ga, gb []int
+		switch {
+		case len(b) == 0 || (len(a) > 0 && intList.less(a[0], b[0])):
+			ga = a[:intList.groupLen(a)]
+		case len(a) == 0 || intList.less(b[0], a[0]):
+			gb = b[:intList.groupLen(b)]
+		default:
+			ga, gb = a[:intList.groupLen(a)], b[:intList.groupLen(b)]
+		}
+		if !f(ga, gb) {
+			return
+		}
+		a, b = a[len(ga):], b[len(gb):]
+	}
+}
+
+// appendMissing appends to dst the values of group that are not in other.
+func appendMissing(dst, group, other []int) []int {
+	for _, value := range group {
+		if !slices.Contains(other, value) {
+			dst = append(dst, value)
+		}
+	}
+	return dst
+}
+
+// dedupe returns values, stably sorted in the list's order, without duplicates: an int is kept
+// once and, for EqualReject and EqualReplace, a group of tied values is reduced to its first or
+// last value, the one Insert would keep.
+func (intList *ConcurrentIntList) dedupe(values []int) []int {
+	if !intList.hasEqualPolicy {
+		return slices.Compact(values)
+	}
+	unique := make([]int, 0, len(values))
+	for len(values) > 0 {
+		group := values[:intList.groupLen(values)]
+		values = values[len(group):]
+		switch intList.equalPolicy {
+		case EqualReject:
+			unique = append(unique, group[0])
+		case EqualReplace:
+			unique = append(unique, group[len(group)-1])
+		default:
+			for i, value := range group {
+				if !slices.Contains(group[:i], value) {
+					unique = append(unique, value)
+				}
+			}
+		}
+	}
+	return unique
+}
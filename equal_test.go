@@ -0,0 +1,202 @@
+package collections
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestWithEqualPolicy(t *testing.T) {
+	// ties every value with those of the same ten
+	byTen := WithComparator(func(a, b int) bool { return a/10 < b/10 })
+	insertAll := func(l *ConcurrentIntList) []bool {
+		var inserted []bool
+		for _, value := range []int{15, 3, 12, 25, 18, 12} {
+			inserted = append(inserted, l.Insert(value))
+		}
+		return inserted
+	}
+
+	allow := NewConcurrentIntList(byTen, WithEqualPolicy(EqualAllow))
+	if got := insertAll(allow); !slices.Equal(got, []bool{true, true, true, true, true, false}) {
+		t.Fatalf("invalid inserts with EqualAllow %v", got)
+	}
+	// ties are kept in insertion order
+	if got := allow.ToSlice(); !slices.Equal(got, []int{3, 15, 12, 18, 25}) {
+		t.Fatalf("invalid contents with EqualAllow %v", got)
+	}
+	for _, value := range []int{15, 12, 18} {
+		if !allow.Contains(value) || !allow.ContainsStrict(value) {
+			t.Fatalf("invalid lookup of tied %d", value)
+		}
+	}
+	if !allow.IsSorted() || allow.HealthCheck() != nil {
+		t.Fatal("invalid check of tied values")
+	}
+	if allow.Contains(11) || allow.Delete(11) || !allow.Delete(12) || allow.Contains(12) || !allow.Contains(18) {
+		t.Fatalf("invalid delete among ties, got %v", allow.ToSlice())
+	}
+
+	reject := NewConcurrentIntList(byTen, WithEqualPolicy(EqualReject))
+	if got := insertAll(reject); !slices.Equal(got, []bool{true, true, false, true, false, false}) {
+		t.Fatalf("invalid inserts with EqualReject %v", got)
+	}
+	if err := reject.InsertE(17); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("invalid insert of a tie %v", err)
+	}
+	if got := reject.ToSlice(); !slices.Equal(got, []int{3, 15, 25}) || reject.Contains(12) {
+		t.Fatalf("invalid contents with EqualReject %v", got)
+	}
+
+	replace := NewConcurrentIntList(byTen, WithEqualPolicy(EqualReplace))
+	if got := insertAll(replace); !slices.Equal(got, []bool{true, true, true, true, true, true}) {
+		t.Fatalf("invalid inserts with EqualReplace %v", got)
+	}
+	if got := replace.ToSlice(); !slices.Equal(got, []int{3, 12, 25}) || replace.Len() != 3 {
+		t.Fatalf("invalid contents with EqualReplace %v", got)
+	}
+	if replace.Contains(15) || replace.Contains(18) || !replace.Contains(12) || replace.HealthCheck() != nil {
+		t.Fatal("invalid lookup after replace")
+	}
+	if inserted, pred, hasPred, succ, hasSucc := replace.InsertWithNeighbors(29); !inserted ||
+		!hasPred || pred != 12 || hasSucc || succ != 0 {
+		t.Fatalf("invalid neighbors of a replace %v %d %v %d %v", inserted, pred, hasPred, succ, hasSucc)
+	}
+
+	// concurrent replaces keep one value per ten
+	var wg sync.WaitGroup
+	for g := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 200 {
+				replace.Insert(i%50*10 + g)
+				replace.Delete((i + 25) % 50 * 10)
+			}
+		}()
+	}
+	wg.Wait()
+	if replace.Len() > 50 || replace.HealthCheck() != nil {
+		t.Fatalf("invalid list after concurrent replaces, %d values", replace.Len())
+	}
+
+	if _, err := NewConcurrentIntListE(WithEqualPolicy(EqualPolicy(7))); !errors.Is(err, ErrInvalidOption) {
+		t.Fatalf("invalid validation of an unknown policy %v", err)
+	}
+}
+
+func TestDistinctLen(t *testing.T) {
+	byTen := WithComparator(func(a, b int) bool { return a/10 < b/10 })
+	for _, c := range []struct {
+		values   []int
+		distinct int
+	}{
+		{[]int{1, 12, 25, 38}, 4},
+		{[]int{20, 21, 22, 29}, 1},
+		{[]int{1, 2, 15, 30, 31, 32, 44}, 4},
+		{nil, 0},
+	} {
+		l := NewConcurrentIntList(byTen, WithEqualPolicy(EqualAllow))
+		for _, value := range c.values {
+			l.Insert(value)
+		}
+		if got := l.DistinctLen(); got != c.distinct || l.Len() != len(c.values) {
+			t.Fatalf("invalid distinct length of %v expected %d, got %d", c.values, c.distinct, got)
+		}
+	}
+	if got := newListOf(1, 2, 3).DistinctLen(); got != 3 {
+		t.Fatalf("invalid distinct length without ties %d", got)
+	}
+}
+
+func TestEqualAllowLookups(t *testing.T) {
+	byTen := WithComparator(func(a, b int) bool { return a/10 < b/10 })
+	newTied := func(values ...int) *ConcurrentIntList {
+		l := NewConcurrentIntList(byTen, WithEqualPolicy(EqualAllow))
+		for _, value := range values {
+			l.Insert(value)
+		}
+		return l
+	}
+	l := newTied(5, 11, 12, 13, 20)
+
+	for _, c := range []struct {
+		value, pred, succ int
+		present           bool
+	}{
+		{11, 5, 12, true}, {12, 11, 13, true}, {13, 12, 20, true}, {14, 13, 20, false},
+	} {
+		pred, hasPred, succ, hasSucc, present := l.Surrounding(c.value)
+		if pred != c.pred || succ != c.succ || !hasPred || !hasSucc || present != c.present {
+			t.Fatalf("invalid surrounding of %d: %d %d %v", c.value, pred, succ, present)
+		}
+	}
+	if got := l.FilterPresent([]int{13, 12, 14, 12, 20}); !slices.Equal(got, []int{12, 13, 20}) {
+		t.Fatalf("invalid filter of tied values %v", got)
+	}
+	for i, value := range []int{5, 11, 12, 13, 20} {
+		if !l.IndexMatches(value, i) {
+			t.Fatalf("invalid index of tied %d expected %d", value, i)
+		}
+	}
+	if l.IndexMatches(13, 1) || l.IndexMatches(14, 4) {
+		t.Fatal("invalid index of a tied value")
+	}
+
+	// the same values inserted in another order
+	other := newTied(20, 13, 11, 12, 5)
+	if _, _, found := l.FirstDifference(other); found {
+		t.Fatal("invalid difference of lists with the same tied values")
+	}
+	if n := l.RetainAllIn(other); n != 0 || l.Len() != 5 {
+		t.Fatalf("invalid retain of the same tied values, removed %d", n)
+	}
+	other.Delete(12)
+	if value, inThisOnly, found := l.FirstDifference(other); !found || value != 12 || !inThisOnly {
+		t.Fatalf("invalid difference of tied values %d %v %v", value, inThisOnly, found)
+	}
+	if got := SymmetricDifference(newTied(11), newTied(12)).ToSlice(); !slices.Equal(got, []int{11, 12}) {
+		t.Fatalf("invalid symmetric difference of tied values %v", got)
+	}
+	if got := SymmetricDifference(l, other).ToSlice(); !slices.Equal(got, []int{12}) {
+		t.Fatalf("invalid symmetric difference %v", got)
+	}
+	if n := l.RetainAllIn(other); n != 1 || l.Contains(12) || !l.Contains(13) {
+		t.Fatalf("invalid retain of tied values, removed %d, got %v", n, l.ToSlice())
+	}
+}
+
+func TestEqualPolicyDedupe(t *testing.T) {
+	byTen := WithComparator(func(a, b int) bool { return a/10 < b/10 })
+	for _, c := range []struct {
+		policy EqualPolicy
+		want   []int
+	}{
+		{EqualAllow, []int{5, 12, 11, 13}},
+		{EqualReject, []int{5, 12}},
+		{EqualReplace, []int{5, 13}},
+	} {
+		l := NewConcurrentIntList(byTen, WithEqualPolicy(c.policy))
+		l.ReplaceAll([]int{12, 5, 11, 12, 13})
+		if got := l.ToSlice(); !slices.Equal(got, c.want) || l.Len() != len(c.want) {
+			t.Fatalf("invalid replace with policy %d: %v", c.policy, got)
+		}
+		mapped := l.MapToList(func(value int) int { return value + 1 })
+		if got := mapped.ToSlice(); len(got) != len(c.want) {
+			t.Fatalf("invalid map with policy %d: %v", c.policy, got)
+		}
+	}
+
+	reject := NewConcurrentIntList(byTen, WithEqualPolicy(EqualReject))
+	reject.ReplaceAll([]int{11, 12})
+	if got := reject.ToSlice(); !slices.Equal(got, []int{11}) || reject.Insert(12) {
+		t.Fatalf("invalid replace of tied values %v", got)
+	}
+	other := NewConcurrentIntList(byTen, WithEqualPolicy(EqualReject))
+	other.Insert(13)
+	// 11 and 13 tie, but are not equal, so both lists hold a value the other lacks
+	if got := SymmetricDifference(reject, other).ToSlice(); !slices.Equal(got, []int{11}) {
+		t.Fatalf("invalid symmetric difference of rejected ties %v", got)
+	}
+}
@@ -0,0 +1,82 @@
+package collections
+
+import "math"
+
+// ConcurrentFloatList is a sorted set of float64 in which values at most epsilon apart are the
+// same element, so rounding noise doesn't insert near duplicates. It is backed by a
+// ConcurrentIntList holding the bits of each value, ordered by < on the floats and built with
+// WithEqualPolicy(EqualReject), and shares its lazy locking.
+//
+// Equality within epsilon is not transitive: with epsilon 1, 0 and 1.5 are different but 0.75
+// equals both. Any two stored values are more than epsilon apart, so the stored set is well
+// ordered, but which values it keeps depends on the insertion order: inserting 0, 1.5 then 0.75
+// keeps 0 and 1.5, inserting 0.75 first keeps only 0.75.
+type ConcurrentFloatList struct {
+	list *ConcurrentIntList
+}
+
+// NewConcurrentFloatListEpsilon returns an empty list treating values at most eps apart as
+// equal, eps lower than 0 is taken as 0, exact equality. -0 and 0 are always equal.
+func NewConcurrentFloatListEpsilon(eps float64) *ConcurrentFloatList {
+	eps = max(eps, 0)
+	list := NewConcurrentIntList(WithComparator(func(a, b int) bool {
+		return floatOf(b)-floatOf(a) > eps
+	}), WithEqualPolicy(EqualReject))
+	return &ConcurrentFloatList{list: list}
+}
+
+func floatKey(value float64) int {
+	return int(math.Float64bits(value))
+}
+
+func floatOf(key int) float64 {
+	return math.Float64frombits(uint64(key))
+}
+
+// Insert adds value unless a stored value is within epsilon of it, it returns false for such a
+// near duplicate and for NaN, which is never stored.
+func (l *ConcurrentFloatList) Insert(value float64) bool {
+	if math.IsNaN(value) {
+		return false
+	}
+	return l.list.Insert(floatKey(value))
+}
+
+// Contains reports whether a stored value is within epsilon of value.
+func (l *ConcurrentFloatList) Contains(value float64) bool {
+	if math.IsNaN(value) {
+		return false
+	}
+	_, ok := l.list.equalOrTied(floatKey(value))
+	return ok
+}
+
+// Delete removes the stored value within epsilon of value.
+func (l *ConcurrentFloatList) Delete(value float64) bool {
+	if math.IsNaN(value) {
+		return false
+	}
+	key, ok := l.list.equalOrTied(floatKey(value))
+	return ok && l.list.Delete(key)
+}
+
+// Range calls f for every stored value in ascending order, stopping when f returns false.
+func (l *ConcurrentFloatList) Range(f func(value float64) bool) {
+	l.list.Range(func(key int) bool {
+		return f(floatOf(key))
+	})
+}
+
+// ToSlice returns the stored values in ascending order.
+func (l *ConcurrentFloatList) ToSlice() []float64 {
+	values := make([]float64, 0, l.Len())
+	l.Range(func(value float64) bool {
+		values = append(values, value)
+		return true
+	})
+	return values
+}
+
+func (l *ConcurrentFloatList) Len() int {
+	return l.list.Len()
+}
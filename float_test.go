@@ -0,0 +1,43 @@
+package collections
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestConcurrentFloatListEpsilon(t *testing.T) {
+	// not a constant, so that it is rounded
+	tenth := 0.1
+	l := NewConcurrentFloatListEpsilon(1e-9)
+	if !l.Insert(0.3) || l.Insert(tenth+0.2) || !l.Insert(0.3+1e-6) || l.Len() != 2 {
+		t.Fatalf("invalid dedup within epsilon, got %v", l.ToSlice())
+	}
+	if !l.Contains(tenth+0.2) || !l.Contains(0.3-5e-10) || l.Contains(0.3-1e-8) {
+		t.Fatal("invalid lookup within epsilon")
+	}
+	if !l.Insert(-1) || !l.Insert(0) || l.Insert(math.Copysign(0, -1)) || l.Insert(math.NaN()) || l.Contains(math.NaN()) {
+		t.Fatalf("invalid insert of special values, got %v", l.ToSlice())
+	}
+	if got := l.ToSlice(); !slices.Equal(got, []float64{-1, 0, 0.3, 0.3 + 1e-6}) {
+		t.Fatalf("invalid order %v", got)
+	}
+	if !l.Delete(0.3+1e-6+1e-10) || l.Contains(0.3+1e-6) || !l.Contains(0.3) || l.Len() != 3 {
+		t.Fatal("invalid delete within epsilon")
+	}
+
+	// equality within epsilon is not transitive, the insertion order decides
+	a := NewConcurrentFloatListEpsilon(1)
+	if !a.Insert(0) || !a.Insert(1.5) || a.Insert(0.75) || !a.Contains(0.75) {
+		t.Fatalf("invalid insert between two values, got %v", a.ToSlice())
+	}
+	b := NewConcurrentFloatListEpsilon(1)
+	if !b.Insert(0.75) || b.Insert(0) || b.Insert(1.5) || b.Len() != 1 {
+		t.Fatalf("invalid insert around a value, got %v", b.ToSlice())
+	}
+
+	exact := NewConcurrentFloatListEpsilon(-1)
+	if !exact.Insert(0.3) || !exact.Insert(tenth+0.2) || exact.Insert(0.3) || exact.Len() != 2 {
+		t.Fatalf("invalid exact list, got %v", exact.ToSlice())
+	}
+}
@@ -0,0 +1,198 @@
+package collections
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrFrozen is returned by write operations refused because the list is frozen.
+var ErrFrozen = errors.New("collections: list is frozen")
+
+// FreezeMode decides what a write does while the list is frozen.
+type FreezeMode int
+
+const (
+	// FreezeBlock makes writes wait until Unfreeze is called, this is the default.
+	FreezeBlock FreezeMode = iota
+	// FreezeReject makes writes fail immediately, Insert and Delete return false and
+	// TryInsert and TryDelete return ErrFrozen.
+	FreezeReject
+)
+
+// WithFreezeMode sets the behavior of writes while the list is frozen.
+func WithFreezeMode(mode FreezeMode) Option {
+	return func(intList *ConcurrentIntList) {
+		intList.freezeMode = mode
+	}
+}
+
+// Freeze makes the list read-only until Unfreeze is called. It waits for in-flight writes to
+// finish, so once it returns Range, Len and the other reads are exact. Reads keep running
+// concurrently during the freeze. Freezing a frozen list is a no-op.
+func (intList *ConcurrentIntList) Freeze() {
+	intList.freezeMu.Lock()
+	defer intList.freezeMu.Unlock()
+	if intList.Frozen() {
+		return
+	}
+	intList.gate.Lock()
+	atomic.StoreInt32(&intList.frozen, 1)
+	// writes waiting with FreezeReject give up now
+	intList.signalGate()
+}
+
+// Unfreeze lets writes proceed again, unfreezing a list that is not frozen is a no-op.
+func (intList *ConcurrentIntList) Unfreeze() {
+	intList.freezeMu.Lock()
+	defer intList.freezeMu.Unlock()
+	if !intList.Frozen() {
+		return
+	}
+	atomic.StoreInt32(&intList.frozen, 0)
+	intList.gate.Unlock()
+	intList.signalGate()
+}
+
+// Frozen reports whether the list is frozen.
+func (intList *ConcurrentIntList) Frozen() bool {
+	return atomic.LoadInt32(&intList.frozen) == 1
+}
+
+// quiesce runs f while no write is in flight, either under the freeze or by briefly taking
+// the gate exclusively.
+func (intList *ConcurrentIntList) quiesce(f func()) {
+	intList.freezeMu.Lock()
+	defer intList.freezeMu.Unlock()
+	if intList.Frozen() {
+		f()
+		return
+	}
+	intList.gate.Lock()
+	defer intList.signalGate()
+	defer intList.gate.Unlock()
+	f()
+}
+
+// RangeLocked is Range holding writes back for the whole walk, so f sees the exact contents of
+// the list, at the cost of blocking writers meanwhile. f must not write to the list. If f
+// panics the list is released before the panic propagates.
+func (intList *ConcurrentIntList) RangeLocked(f func(value int) bool) {
+	intList.quiesce(func() {
+		intList.Range(f)
+	})
+}
+
+// IndexMatches reports whether value is present at position index of the list's order, 0 being
+// the first. The walk holds writes back like RangeLocked, so unlike separate lookups the answer
+// is for one state of the list.
+func (intList *ConcurrentIntList) IndexMatches(value, index int) bool {
+	value = intList.canon(value)
+	var matches bool
+	intList.quiesce(func() {
+		i := 0
+		intList.Range(func(v int) bool {
+			if v == value {
+				matches = i == index
+				return false
+			}
+			i++
+			return i <= index && intList.before(v, value)
+		})
+	})
+	return matches
+}
+
+// exclusiveWrite runs f as a write excluding every other write. Like a regular write it waits
+// for, or with FreezeReject refuses, a frozen list and is refused once the list is closed.
+func (intList *ConcurrentIntList) exclusiveWrite(f func()) error {
+	if intList.freezeMode == FreezeBlock {
+		intList.gate.Lock()
+	} else if err := intList.waitGate(intList.gate.TryLock, time.Time{}); err != nil {
+		return err
+	}
+	defer intList.signalGate()
+	defer intList.gate.Unlock()
+	if intList.Closed() {
+		return ErrClosed
+	}
+	f()
+	return nil
+}
+
+// beginWrite must be paired with endWrite if it returns nil. A write waiting on a frozen list
+// gives up with ErrTimeout once deadline is passed, a zero deadline never expires.
+func (intList *ConcurrentIntList) beginWrite(deadline time.Time) error {
+	if intList.freezeMode == FreezeBlock && deadline.IsZero() {
+		intList.gate.RLock()
+	} else if err := intList.waitGate(intList.gate.TryRLock, deadline); err != nil {
+		return err
+	}
+	if intList.Closed() {
+		intList.endWrite()
+		return ErrClosed
+	}
+	return nil
+}
+
+func (intList *ConcurrentIntList) endWrite() {
+	intList.gate.RUnlock()
+	intList.signalGate()
+}
+
+// waitGate calls take until it gets the gate, sleeping between attempts until the gate is
+// released or the list frozen. With FreezeReject it gives up with ErrFrozen once the list is
+// frozen, and with ErrTimeout once deadline is passed, a zero deadline never expires. The gate
+// can also be briefly unavailable while a Freeze or an exclusive write waits for in-flight
+// writes.
+func (intList *ConcurrentIntList) waitGate(take func() bool, deadline time.Time) error {
+	atomic.AddInt32(&intList.gateWaiters, 1)
+	defer atomic.AddInt32(&intList.gateWaiters, -1)
+	var timeout <-chan time.Time
+	if !deadline.IsZero() {
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	for {
+		// taken before the attempt, a release after a failed attempt closes it
+		changed := intList.gateChanged()
+		if take() {
+			return nil
+		}
+		if intList.freezeMode == FreezeReject && intList.Frozen() {
+			return ErrFrozen
+		}
+		if expired(deadline) {
+			return ErrTimeout
+		}
+		select {
+		case <-changed:
+		case <-timeout:
+		}
+	}
+}
+
+// gateChanged returns a channel closed by the next signalGate.
+func (intList *ConcurrentIntList) gateChanged() <-chan struct{} {
+	intList.gateMu.Lock()
+	defer intList.gateMu.Unlock()
+	if intList.gateFree == nil {
+		intList.gateFree = make(chan struct{})
+	}
+	return intList.gateFree
+}
+
+// signalGate wakes the writes in waitGate after the gate is released or the list frozen. It
+// only loads a counter when none is waiting, a waiter is counted before its first attempt.
+func (intList *ConcurrentIntList) signalGate() {
+	if atomic.LoadInt32(&intList.gateWaiters) == 0 {
+		return
+	}
+	intList.gateMu.Lock()
+	defer intList.gateMu.Unlock()
+	if intList.gateFree != nil {
+		close(intList.gateFree)
+		intList.gateFree = nil
+	}
+}
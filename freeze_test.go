@@ -0,0 +1,187 @@
+package collections
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFreeze(t *testing.T) {
+	l := NewConcurrentIntList()
+	l.Insert(1)
+	l.Insert(2)
+
+	l.Freeze()
+	l.Freeze()
+	if !l.Frozen() {
+		t.Fatal("invalid frozen")
+	}
+	done := make(chan bool)
+	go func() {
+		done <- l.Insert(3)
+	}()
+	select {
+	case <-done:
+		t.Fatal("writer doesn't block while frozen")
+	case <-time.After(50 * time.Millisecond):
+	}
+	// Reads proceed during freeze.
+	if !l.Contains(1) || l.Contains(3) || l.Len() != 2 {
+		t.Fatal("invalid read while frozen")
+	}
+	l.Unfreeze()
+	if !<-done || !l.Contains(3) || l.Len() != 3 {
+		t.Fatal("invalid insert after unfreeze")
+	}
+	l.Unfreeze()
+	if l.Frozen() {
+		t.Fatal("invalid frozen")
+	}
+
+	r := NewConcurrentIntList(WithFreezeMode(FreezeReject))
+	r.Insert(1)
+	r.Freeze()
+	if r.Insert(2) || r.Delete(1) {
+		t.Fatal("writer doesn't reject while frozen")
+	}
+	if ok, err := r.TryInsert(2); ok || !errors.Is(err, ErrFrozen) {
+		t.Fatalf("invalid insert %v, %v", ok, err)
+	}
+	if ok, err := r.TryDelete(1); ok || !errors.Is(err, ErrFrozen) {
+		t.Fatalf("invalid delete %v, %v", ok, err)
+	}
+	if r.InsertSorted([]int{2, 3}) != 0 || r.Len() != 1 {
+		t.Fatal("invalid insert sorted while frozen")
+	}
+	r.Unfreeze()
+	if ok, err := r.TryInsert(2); !ok || err != nil {
+		t.Fatalf("invalid insert %v, %v", ok, err)
+	}
+	if ok, err := r.TryDelete(1); !ok || err != nil {
+		t.Fatalf("invalid delete %v, %v", ok, err)
+	}
+}
+
+func TestRangeLockedPanic(t *testing.T) {
+	l := NewConcurrentIntList()
+	for i := 0; i < 10; i++ {
+		l.Insert(i)
+	}
+	var values []int
+	l.RangeLocked(func(value int) bool {
+		values = append(values, value)
+		return true
+	})
+	if len(values) != 10 {
+		t.Fatalf("invalid locked range of %d values", len(values))
+	}
+
+	panicking := func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("invalid recover, expected a panic")
+			}
+		}()
+		l.RangeLocked(func(value int) bool {
+			panic("callback panic")
+		})
+	}
+	panicking()
+	if !l.Insert(10) || !l.Delete(0) {
+		t.Fatal("invalid write after a panic in a locked range")
+	}
+
+	// Under a freeze the list stays frozen and can still be unfrozen.
+	l.Freeze()
+	panicking()
+	if !l.Frozen() {
+		t.Fatal("invalid freeze after a panic in a locked range")
+	}
+	l.Unfreeze()
+	if !l.Insert(11) || l.HealthCheck() != nil {
+		t.Fatal("invalid write after unfreeze")
+	}
+}
+
+func TestIndexMatches(t *testing.T) {
+	l := newListOf(10, 20, 30, 40)
+	for i, value := range []int{10, 20, 30, 40} {
+		if !l.IndexMatches(value, i) {
+			t.Fatalf("invalid index of %d expected %d", value, i)
+		}
+	}
+	for _, c := range [][2]int{{10, 1}, {30, 1}, {30, 3}, {40, 0}, {25, 2}, {50, 4}, {10, -1}} {
+		if l.IndexMatches(c[0], c[1]) {
+			t.Fatalf("invalid index %d of %d", c[1], c[0])
+		}
+	}
+	l.Delete(20)
+	if !l.IndexMatches(30, 1) || l.IndexMatches(30, 2) {
+		t.Fatal("invalid index after a delete")
+	}
+
+	// a frozen list is walked under its freeze
+	l.Freeze()
+	if !l.IndexMatches(40, 2) {
+		t.Fatal("invalid index of a frozen list")
+	}
+	l.Unfreeze()
+}
+
+func TestWaitGate(t *testing.T) {
+	// a write waiting with a deadline sleeps until Unfreeze instead of polling
+	l := NewConcurrentIntList()
+	l.Freeze()
+	done := make(chan error)
+	go func() {
+		_, err := l.InsertTimeout(1, time.Minute)
+		done <- err
+	}()
+	for atomic.LoadInt32(&l.gateWaiters) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	l.Unfreeze()
+	select {
+	case err := <-done:
+		if err != nil || !l.Contains(1) {
+			t.Fatalf("invalid insert after unfreeze %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("write still waiting after unfreeze")
+	}
+
+	// with FreezeReject, a write waiting for the gate gives up once a Freeze takes it
+	r := NewConcurrentIntList(WithFreezeMode(FreezeReject))
+	reached, release := make(chan struct{}), make(chan struct{})
+	r.Insert(1)
+	testHookBeforeLink = func() {
+		close(reached)
+		<-release
+	}
+	go r.Insert(2)
+	<-reached
+	testHookBeforeLink = nil
+	go func() {
+		done <- r.ReplaceAll([]int{3})
+	}()
+	for atomic.LoadInt32(&r.gateWaiters) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	frozen := make(chan struct{})
+	go func() {
+		r.Freeze()
+		close(frozen)
+	}()
+	// let the Freeze queue behind the paused insert
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	if err := <-done; !errors.Is(err, ErrFrozen) {
+		t.Fatalf("invalid exclusive write while freezing %v", err)
+	}
+	<-frozen
+	r.Unfreeze()
+	if got := r.ToSlice(); len(got) != 2 {
+		t.Fatalf("invalid contents %v", got)
+	}
+}
@@ -0,0 +1,83 @@
+package collections
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrCorrupt is wrapped by the errors HealthCheck returns.
+var ErrCorrupt = errors.New("collections: list is corrupt")
+
+// HealthCheck walks the list while no write is in flight and returns an error describing the
+// first broken invariant: a node out of order, a deleted node still linked, or a size that
+// doesn't match the number of nodes. It costs one walk and holds writes back meanwhile, so it
+// suits a periodic readiness probe on moderate lists.
+func (intList *ConcurrentIntList) HealthCheck() error {
+	var err error
+	intList.quiesce(func() {
+		var count int64
+		pre := intList.root
+		for n := pre.next(); n != nil; pre, n = n, n.next() {
+			if n.marked() {
+				err = fmt.Errorf("%w: deleted node %d still linked", ErrCorrupt, n.value)
+				return
+			}
+			if pre != intList.root && !intList.before(pre.value, n.value) {
+				err = fmt.Errorf("%w: %d is not ordered after %d", ErrCorrupt, n.value, pre.value)
+				return
+			}
+			count++
+		}
+		if size := atomic.LoadInt64(&intList.size); size != count {
+			err = fmt.Errorf("%w: size is %d but %d nodes are linked", ErrCorrupt, size, count)
+		}
+	})
+	return err
+}
+
+// approxLenSample is the number of linked nodes ApproxLen looks at.
+const approxLenSample = 256
+
+// ErrEstimated is wrapped by the error ApproxLen returns when it extrapolated the length.
+var ErrEstimated = errors.New("collections: length extrapolated from a sample")
+
+// ApproxLen estimates the number of values without a full walk. Len is trusted as long as
+// deleted nodes don't linger: the first approxLenSample linked nodes are sampled and, if some
+// are deleted but still linked, Len is scaled down by the fraction of live nodes in the sample
+// and the error wraps ErrEstimated. A Delete in flight can cause that error for a moment, if it
+// persists nodes linger and Len overcounts them, see Compact and LenError. A list shorter than
+// the sample is counted exactly.
+func (intList *ConcurrentIntList) ApproxLen() (int, error) {
+	var live, linked int
+	n := intList.root.next()
+	for ; n != nil && linked < approxLenSample; n = n.next() {
+		linked++
+		if !n.marked() {
+			live++
+		}
+	}
+	if n == nil {
+		return live, nil
+	}
+	if live == linked {
+		return intList.Len(), nil
+	}
+	estimate := int(float64(intList.Len()) * float64(live) / float64(linked))
+	return estimate, fmt.Errorf("%w: %d of %d sampled nodes are deleted but still linked",
+		ErrEstimated, linked-live, linked)
+}
+
+// LenError returns how far Len is from the number of values, counted by a walk while no write
+// is in flight. It is 0 on a healthy list, deleted nodes still linked make it positive.
+func (intList *ConcurrentIntList) LenError() int {
+	var drift int64
+	intList.quiesce(func() {
+		drift = atomic.LoadInt64(&intList.size)
+		intList.Range(func(int) bool {
+			drift--
+			return true
+		})
+	})
+	return int(drift)
+}
@@ -0,0 +1,118 @@
+package collections
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestHealthCheck(t *testing.T) {
+	l := NewConcurrentIntList()
+	if err := l.HealthCheck(); err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			for i := 0; i < 1000; i++ {
+				l.Insert(int(fastrandn(64)))
+				l.Delete(int(fastrandn(64)))
+				if i%100 == 0 {
+					if err := l.HealthCheck(); err != nil {
+						panic(err)
+					}
+				}
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+	if err := l.HealthCheck(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Skewed size.
+	l = NewConcurrentIntList()
+	for i := 0; i < 10; i++ {
+		l.Insert(i)
+	}
+	atomic.AddInt64(&l.size, 1)
+	if err := l.HealthCheck(); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("invalid health check with skewed size %v", err)
+	}
+	atomic.AddInt64(&l.size, -1)
+
+	// Node out of order.
+	first := l.root.next()
+	wrong := newIntNode(100)
+	wrong.updateNext(first.next())
+	first.updateNext(wrong)
+	atomic.AddInt64(&l.size, 1)
+	if err := l.HealthCheck(); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("invalid health check with unordered node %v", err)
+	}
+	first.updateNext(wrong.next())
+	atomic.AddInt64(&l.size, -1)
+
+	// Deleted node left linked.
+	first.next().mark()
+	if err := l.HealthCheck(); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("invalid health check with deleted node %v", err)
+	}
+
+	// Frozen lists are checked under the freeze.
+	l = NewConcurrentIntList()
+	l.Insert(1)
+	l.Freeze()
+	if err := l.HealthCheck(); err != nil {
+		t.Fatal(err)
+	}
+	l.Unfreeze()
+}
+
+func TestApproxLen(t *testing.T) {
+	l := NewConcurrentIntList()
+	if n, err := l.ApproxLen(); n != 0 || err != nil || l.LenError() != 0 {
+		t.Fatalf("invalid approximate length of an empty list %d, %v", n, err)
+	}
+	values := make([]int, 4*approxLenSample)
+	for i := range values {
+		values[i] = i
+	}
+	l.InsertSorted(values)
+	if n, err := l.ApproxLen(); n != len(values) || err != nil || l.LenError() != 0 {
+		t.Fatalf("invalid approximate length %d, %v", n, err)
+	}
+
+	// Every fourth node marked by hand, as Deletes that never got to unlink them.
+	for n := l.root.next(); n != nil; n = n.next() {
+		if n.value%4 == 0 {
+			n.mark()
+		}
+	}
+	if drift := l.LenError(); drift != len(values)/4 {
+		t.Fatalf("invalid drift expected %d, got %d", len(values)/4, drift)
+	}
+	if l.Len() != len(values) {
+		t.Fatal("invalid length")
+	}
+	n, err := l.ApproxLen()
+	if !errors.Is(err, ErrEstimated) || errors.Is(err, ErrCorrupt) || n != len(values)*3/4 {
+		t.Fatalf("invalid approximate length with lingering nodes %d, %v", n, err)
+	}
+	if l.Compact() != len(values)/4 || l.LenError() != 0 {
+		t.Fatal("invalid drift after compact")
+	}
+	if n, err := l.ApproxLen(); n != len(values)*3/4 || err != nil {
+		t.Fatalf("invalid approximate length after compact %d, %v", n, err)
+	}
+
+	// A list shorter than the sample is counted exactly, lingering nodes left out.
+	l = newListOf(1, 2, 3)
+	l.root.next().mark()
+	if n, err := l.ApproxLen(); n != 2 || err != nil || l.LenError() != 1 {
+		t.Fatalf("invalid approximate length of a short list %d, %v", n, err)
+	}
+}
@@ -0,0 +1,228 @@
+package collections
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+type intMapNode[V any] struct {
+	key         int
+	valuePtr    atomic.Value
+	nextPtr     atomic.Value
+	markedValue atomic.Value
+	mutex       sync.Mutex
+}
+
+func (n *intMapNode[V]) mark() {
+	n.markedValue.Store(true)
+}
+
+func (n *intMapNode[V]) marked() bool {
+	b, ok := n.markedValue.Load().(bool)
+	return b && ok
+}
+
+func (n *intMapNode[V]) next() *intMapNode[V] {
+	nxt, _ := n.nextPtr.Load().(*intMapNode[V])
+	return nxt
+}
+
+func (n *intMapNode[V]) updateNext(next *intMapNode[V]) {
+	n.nextPtr.Store(next)
+}
+
+func (n *intMapNode[V]) value() V {
+	v, _ := n.valuePtr.Load().(*V)
+	if v == nil {
+		var zero V
+		return zero
+	}
+	return *v
+}
+
+func (n *intMapNode[V]) updateValue(value V) {
+	n.valuePtr.Store(&value)
+}
+
+func newIntMapNode[V any](key int) *intMapNode[V] {
+	return &intMapNode[V]{key: key}
+}
+
+// ConcurrentIntMap is a sorted map keyed by int, built on the same lazy locking as
+// ConcurrentIntList. A node's value is only replaced while holding the node's lock.
+type ConcurrentIntMap[V any] struct {
+	root *intMapNode[V]
+	size int64
+	// onDuplicate resolves a Put on a present key, nil keeps the existing value
+	onDuplicate func(existing, incoming V) V
+}
+
+// MapOption configures a ConcurrentIntMap at construction.
+type MapOption[V any] func(intMap *ConcurrentIntMap[V])
+
+// WithOnDuplicate makes Put on a present key store resolve(existing, incoming) instead of keeping
+// the existing value, e.g. the max of both or their sum. resolve runs under the node's lock like
+// an Upsert callback, so it must not call back into the map.
+func WithOnDuplicate[V any](resolve func(existing, incoming V) V) MapOption[V] {
+	return func(intMap *ConcurrentIntMap[V]) {
+		intMap.onDuplicate = resolve
+	}
+}
+
+func NewConcurrentIntMap[V any](opts ...MapOption[V]) *ConcurrentIntMap[V] {
+	intMap := &ConcurrentIntMap[V]{root: newIntMapNode[V](-1)}
+	for _, opt := range opts {
+		opt(intMap)
+	}
+	return intMap
+}
+
+// Load returns the value stored for key, ok is false if key is absent.
+func (intMap *ConcurrentIntMap[V]) Load(key int) (value V, ok bool) {
+	n := intMap.root.next()
+	for n != nil && (n.marked() || n.key < key) {
+		n = n.next()
+	}
+	if n == nil || n.key != key {
+		return value, false
+	}
+	return n.value(), true
+}
+
+// Store sets the value for key, inserting key if it is absent.
+func (intMap *ConcurrentIntMap[V]) Store(key int, value V) {
+	intMap.Upsert(key, func(V, bool) V {
+		return value
+	})
+}
+
+// Put inserts key with value and reports true, or if key is present keeps the existing value,
+// or stores the one chosen by the WithOnDuplicate resolver, and reports false. It returns the
+// value stored for key.
+func (intMap *ConcurrentIntMap[V]) Put(key int, value V) (stored V, inserted bool) {
+	stored = intMap.Upsert(key, func(old V, existed bool) V {
+		switch {
+		case !existed:
+			inserted = true
+			return value
+		case intMap.onDuplicate != nil:
+			return intMap.onDuplicate(old, value)
+		default:
+			return old
+		}
+	})
+	return stored, inserted
+}
+
+// Upsert atomically replaces the value of key by f(old, true), or inserts f(zero, false) if key
+// is absent, and returns the stored value. f runs while holding the lock of the node (or of its
+// predecessor when inserting), so concurrent upserts on the same key are serialized; f must not
+// call back into the map. If f panics the lock is released and the map is left unchanged.
+func (intMap *ConcurrentIntMap[V]) Upsert(key int, f func(old V, existed bool) V) V {
+start:
+	pre := intMap.root
+	current := pre.next()
+	// step1: find first node not less than key
+	for current != nil && current.key < key {
+		pre = current
+		current = pre.next()
+	}
+	// step2: update in place
+	if current != nil && current.key == key {
+		current.mutex.Lock()
+		if current.marked() {
+			current.mutex.Unlock()
+			goto start
+		}
+		return current.updateLocked(f)
+	}
+	// step3: insert after pre
+	pre.mutex.Lock()
+	if pre.next() != current || pre.marked() || (current != nil && current.marked()) {
+		pre.mutex.Unlock()
+		goto start
+	}
+	defer pre.mutex.Unlock()
+	var zero V
+	value := f(zero, false)
+	newNode := newIntMapNode[V](key)
+	newNode.updateValue(value)
+	newNode.updateNext(current)
+	atomic.AddInt64(&intMap.size, 1)
+	pre.updateNext(newNode)
+	return value
+}
+
+// updateLocked replaces the value of n by f(old, true) and unlocks n, even if f panics.
+func (n *intMapNode[V]) updateLocked(f func(old V, existed bool) V) V {
+	defer n.mutex.Unlock()
+	value := f(n.value(), true)
+	n.updateValue(value)
+	return value
+}
+
+func (intMap *ConcurrentIntMap[V]) Delete(key int) bool {
+start:
+	pre := intMap.root
+	current := pre.next()
+	for current != nil && (current.marked() || current.key < key) {
+		pre = current
+		current = pre.next()
+	}
+	if current == nil || current.key != key {
+		return false
+	}
+	current.mutex.Lock()
+	if current.marked() {
+		current.mutex.Unlock()
+		goto start
+	}
+	pre.mutex.Lock()
+	if pre.next() != current || pre.marked() {
+		// anti flow, avoid dead lock
+		pre.mutex.Unlock()
+		current.mutex.Unlock()
+		goto start
+	}
+	current.mark()
+	pre.updateNext(current.next())
+	atomic.AddInt64(&intMap.size, -1)
+	pre.mutex.Unlock()
+	current.mutex.Unlock()
+	return true
+}
+
+// Range calls f for every key and value in ascending key order, stopping when f returns false.
+func (intMap *ConcurrentIntMap[V]) Range(f func(key int, value V) bool) {
+	for n := intMap.root.next(); n != nil; n = n.next() {
+		if !n.marked() && !f(n.key, n.value()) {
+			return
+		}
+	}
+}
+
+// RangePayload calls f for every key in ascending order with a pointer to its value, so f can
+// update the value in place, stopping when f returns false. Each call holds the node's lock,
+// so it is serialized with Upsert on the same key; f must not call back into the map.
+// The pointer is only valid during the call, writes through it afterwards are lost or racy.
+func (intMap *ConcurrentIntMap[V]) RangePayload(f func(key int, payload *V) bool) {
+	for n := intMap.root.next(); n != nil; n = n.next() {
+		n.mutex.Lock()
+		if n.marked() {
+			n.mutex.Unlock()
+			continue
+		}
+		goOn := true
+		n.updateLocked(func(payload V, _ bool) V {
+			goOn = f(n.key, &payload)
+			return payload
+		})
+		if !goOn {
+			return
+		}
+	}
+}
+
+func (intMap *ConcurrentIntMap[V]) Len() int {
+	return int(atomic.LoadInt64(&intMap.size))
+}
@@ -0,0 +1,226 @@
+package collections
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestIntMap(t *testing.T) {
+	m := NewConcurrentIntMap[string]()
+	if _, ok := m.Load(1); ok || m.Len() != 0 || m.Delete(1) {
+		t.Fatal("invalid empty map")
+	}
+
+	m.Store(2, "b")
+	m.Store(1, "a")
+	m.Store(3, "c")
+	m.Store(2, "B")
+	if m.Len() != 3 {
+		t.Fatal("invalid length")
+	}
+	if v, ok := m.Load(2); !ok || v != "B" {
+		t.Fatalf("invalid load %q", v)
+	}
+
+	var keys []int
+	m.Range(func(key int, value string) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if len(keys) != 3 || keys[0] != 1 || keys[1] != 2 || keys[2] != 3 {
+		t.Fatalf("invalid range %v", keys)
+	}
+
+	if !m.Delete(2) || m.Delete(2) || m.Len() != 2 {
+		t.Fatal("invalid delete")
+	}
+	if _, ok := m.Load(2); ok {
+		t.Fatal("invalid load after delete")
+	}
+
+	if v := m.Upsert(4, func(old string, existed bool) string {
+		if existed || old != "" {
+			t.Fatal("invalid upsert on absent key")
+		}
+		return "d"
+	}); v != "d" {
+		t.Fatal("invalid upsert")
+	}
+	if v := m.Upsert(4, func(old string, existed bool) string {
+		if !existed || old != "d" {
+			t.Fatal("invalid upsert on present key")
+		}
+		return old + "d"
+	}); v != "dd" {
+		t.Fatal("invalid upsert")
+	}
+}
+
+func TestIntMapUpsertCounter(t *testing.T) {
+	m := NewConcurrentIntMap[int]()
+	const (
+		goroutines = 64
+		increments = 1000
+		keys       = 4
+	)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			for i := 0; i < increments; i++ {
+				m.Upsert(i%keys, func(old int, _ bool) int {
+					return old + 1
+				})
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	var sum int
+	m.Range(func(key int, value int) bool {
+		if value != goroutines*increments/keys {
+			t.Fatalf("invalid counter for %d: %d", key, value)
+		}
+		sum += value
+		return true
+	})
+	if sum != goroutines*increments || m.Len() != keys {
+		t.Fatalf("invalid sum %d", sum)
+	}
+}
+
+func TestIntMapPut(t *testing.T) {
+	m := NewConcurrentIntMap[string]()
+	if stored, inserted := m.Put(1, "a"); !inserted || stored != "a" {
+		t.Fatal("invalid put of absent key")
+	}
+	if stored, inserted := m.Put(1, "b"); inserted || stored != "a" {
+		t.Fatal("invalid put of present key, expected the existing value kept")
+	}
+
+	// Keep max, run concurrently on the same key.
+	maxMap := NewConcurrentIntMap[int](WithOnDuplicate(func(existing, incoming int) int {
+		return max(existing, incoming)
+	}))
+	// Sum payload.
+	type payload struct {
+		count, total int
+	}
+	sumMap := NewConcurrentIntMap[payload](WithOnDuplicate(func(existing, incoming payload) payload {
+		return payload{count: existing.count + incoming.count, total: existing.total + incoming.total}
+	}))
+	const (
+		goroutines = 32
+		puts       = 1000
+	)
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			for i := 0; i < puts; i++ {
+				maxMap.Put(7, g*puts+i)
+				sumMap.Put(7, payload{count: 1, total: i})
+			}
+			wg.Done()
+		}(g)
+	}
+	wg.Wait()
+	if value, _ := maxMap.Load(7); value != goroutines*puts-1 || maxMap.Len() != 1 {
+		t.Fatalf("invalid max expected %d, got %d", goroutines*puts-1, value)
+	}
+	p, _ := sumMap.Load(7)
+	if p.count != goroutines*puts || p.total != goroutines*puts*(puts-1)/2 || sumMap.Len() != 1 {
+		t.Fatalf("invalid sum payload %+v", p)
+	}
+}
+
+func TestIntMapRangePayload(t *testing.T) {
+	type payload struct {
+		hits int
+		name string
+	}
+	m := NewConcurrentIntMap[payload]()
+	for i := 0; i < 5; i++ {
+		m.Store(i, payload{name: "n"})
+	}
+
+	m.RangePayload(func(key int, p *payload) bool {
+		p.hits += key
+		p.name += "!"
+		return true
+	})
+	m.Range(func(key int, p payload) bool {
+		if p.hits != key || p.name != "n!" {
+			t.Fatalf("invalid payload for %d: %+v", key, p)
+		}
+		return true
+	})
+
+	// Stop early, later payloads are untouched.
+	m.RangePayload(func(key int, p *payload) bool {
+		p.hits = -1
+		return key < 1
+	})
+	if p, _ := m.Load(1); p.hits != -1 {
+		t.Fatal("invalid payload")
+	}
+	if p, _ := m.Load(2); p.hits != 2 {
+		t.Fatal("invalid payload after stop")
+	}
+
+	// Concurrent walks and upserts are serialized per node.
+	counters := NewConcurrentIntMap[int]()
+	counters.Store(0, 0)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			for i := 0; i < 100; i++ {
+				counters.RangePayload(func(_ int, v *int) bool {
+					*v++
+					return true
+				})
+			}
+			wg.Done()
+		}()
+		go func() {
+			for i := 0; i < 100; i++ {
+				counters.Upsert(0, func(old int, _ bool) int { return old + 1 })
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+	if v, _ := counters.Load(0); v != 1600 {
+		t.Fatalf("invalid counter %d", v)
+	}
+}
+
+func TestIntMapCallbackPanic(t *testing.T) {
+	m := NewConcurrentIntMap[int]()
+	m.Store(1, 1)
+	mustPanic := func(f func()) {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("invalid recover, expected a panic")
+			}
+		}()
+		f()
+	}
+	mustPanic(func() {
+		m.Upsert(1, func(int, bool) int { panic("update panic") })
+	})
+	mustPanic(func() {
+		m.Upsert(2, func(int, bool) int { panic("insert panic") })
+	})
+	mustPanic(func() {
+		m.RangePayload(func(int, *int) bool { panic("payload panic") })
+	})
+	// every lock has been released and nothing was changed
+	m.Store(1, 10)
+	m.Store(2, 20)
+	if v, _ := m.Load(1); v != 10 || m.Len() != 2 || !m.Delete(2) {
+		t.Fatal("invalid map after callback panics")
+	}
+}
@@ -0,0 +1,253 @@
+package collections
+
+import (
+	"context"
+	"iter"
+	"slices"
+	"time"
+)
+
+// All returns an iterator over the values in the list's order, with the same semantics as Range.
+func (intList *ConcurrentIntList) All() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		intList.Range(yield)
+	}
+}
+
+// Backward returns an iterator over the values in the reverse of the list's order, descending
+// for NewConcurrentIntList. The list is singly linked, so the iterator takes a snapshot when it
+// starts and emits it from the end, it never observes modifications made after that.
+func (intList *ConcurrentIntList) Backward() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		values := intList.ToSlice()
+		for i := len(values) - 1; i >= 0; i-- {
+			if !yield(values[i]) {
+				return
+			}
+		}
+	}
+}
+
+// IterBuffered returns a channel receiving the values in the list's order, with the same
+// semantics as Range, sent by a goroutine through a buffer of bufSize values, bufSize lower than
+// 0 meaning unbuffered. The channel is closed once the walk is over, ctx is done or the list is
+// closed: a consumer stopping early must cancel ctx, the producer then exits even if it is
+// blocked on a full buffer, and the values it buffered are left unread.
+func (intList *ConcurrentIntList) IterBuffered(ctx context.Context, bufSize int) <-chan int {
+	ch := make(chan int, max(bufSize, 0))
+	go func() {
+		defer close(ch)
+		intList.Range(func(value int) bool {
+			if ctx.Err() != nil || intList.Closed() {
+				// select picks at random, a reader draining the channel must not keep it going
+				return false
+			}
+			select {
+			case ch <- value:
+				return true
+			case <-ctx.Done():
+				return false
+			case <-intList.done:
+				return false
+			}
+		})
+	}()
+	return ch
+}
+
+// RangeBatch collects up to batch values in the list's order and calls f once per batch,
+// stopping when f returns false. The slice passed to f is reused by the next call, callers
+// must copy it if they retain it. batch lower than 1 is treated as 1.
+func (intList *ConcurrentIntList) RangeBatch(batch int, f func(values []int) bool) {
+	if batch < 1 {
+		batch = 1
+	}
+	buf := make([]int, 0, batch)
+	for n := intList.root.next(); n != nil; n = n.next() {
+		if n.marked() {
+			continue
+		}
+		buf = append(buf, n.value)
+		if len(buf) == batch {
+			if !f(buf) {
+				return
+			}
+			buf = buf[:0]
+		}
+	}
+	if len(buf) > 0 {
+		f(buf)
+	}
+}
+
+// RangeSample calls f on every step-th value (the step-th, the 2*step-th, ...) in the list's
+// order, stopping when f returns false. It still walks every node, only the callbacks are
+// skipped. step lower than 1 is treated as 1.
+func (intList *ConcurrentIntList) RangeSample(step int, f func(value int) bool) {
+	if step < 1 {
+		step = 1
+	}
+	var i int
+	intList.Range(func(value int) bool {
+		i++
+		if i%step != 0 {
+			return true
+		}
+		return f(value)
+	})
+}
+
+// RangeWhile calls f for every value in the list's order as long as pred holds, stopping at the
+// first value for which pred is false or when f returns false. pred is assumed to be monotonic
+// in the list's order, e.g. value < limit for an ascending list, so the tail is never walked.
+func (intList *ConcurrentIntList) RangeWhile(pred func(value int) bool, f func(value int) bool) {
+	intList.Range(func(value int) bool {
+		return pred(value) && f(value)
+	})
+}
+
+// RangeWithLast is Range also telling f whether value is the last one, found by looking one live
+// node ahead. Under concurrent writes isLast only reflects the list when the next node was read.
+func (intList *ConcurrentIntList) RangeWithLast(f func(value int, isLast bool) bool) {
+	for n := nextLive(intList.root.next()); n != nil; {
+		next := nextLive(n.next())
+		if !f(n.value, next == nil) {
+			return
+		}
+		n = next
+	}
+}
+
+// InsertSeq inserts every value of seq and returns how many were inserted.
+// seq is assumed to follow the list's order (ascending for NewConcurrentIntList), so each
+// insert continues the walk from where the previous one stopped and the whole sequence is
+// merged in a single forward pass. A value that breaks the order is still inserted correctly,
+// but its walk falls back to starting from the head.
+// Values refused because they are out of the domain, or because the list is frozen in
+// FreezeReject mode or closed, are skipped.
+func (intList *ConcurrentIntList) InsertSeq(seq iter.Seq[int]) int {
+	return intList.insertIndexed(func(yield func(int, int) bool) {
+		var i int
+		for value := range seq {
+			if !yield(i, value) {
+				return
+			}
+			i++
+		}
+	}, nil)
+}
+
+// insertIndexed is InsertSeq over the values of seq, calling inserted, if not nil, with the
+// index of every value it inserts.
+func (intList *ConcurrentIntList) insertIndexed(seq iter.Seq2[int, int], inserted func(i int)) int {
+	var (
+		count int
+		ok    bool
+		gen   uint64
+	)
+	hint := intList.root
+	for i, value := range seq {
+		value = intList.canon(value)
+		if hint != intList.root && !intList.less(hint.value, value) {
+			hint = intList.root
+		}
+		if !intList.inDomain(value) || intList.beginWrite(time.Time{}) != nil {
+			continue
+		}
+		// the hint comes from the previous write, the list may have been relinked since
+		hint, gen = intList.keepHint(hint, gen)
+		hint, ok, _ = intList.insertAfter(hint, value, time.Time{}, nil)
+		intList.endWrite()
+		if ok {
+			count++
+			if inserted != nil {
+				inserted(i)
+			}
+		}
+	}
+	return count
+}
+
+// InsertSorted inserts values, assumed to follow the list's order, see InsertSeq.
+func (intList *ConcurrentIntList) InsertSorted(values []int) int {
+	return intList.InsertSeq(slices.Values(values))
+}
+
+// InsertSortedDesc inserts values, assumed to be in descending order, so the reverse of the
+// order of a NewConcurrentIntList. They are walked from the end to merge them in a single
+// forward pass like InsertSorted.
+func (intList *ConcurrentIntList) InsertSortedDesc(values []int) int {
+	return intList.InsertSeq(func(yield func(int) bool) {
+		for i := len(values) - 1; i >= 0; i-- {
+			if !yield(values[i]) {
+				return
+			}
+		}
+	})
+}
+
+// InsertBatchResult inserts values, in any order, and reports for each one whether it was newly
+// inserted. Like InsertSorted it merges them in a single forward pass, unsorted values are
+// visited through a sorted copy of their indices. A value repeated in values is reported true
+// at most once, at its first index, and refused values are reported false like InsertSeq.
+func (intList *ConcurrentIntList) InsertBatchResult(values []int) []bool {
+	results := make([]bool, len(values))
+	inserted := func(i int) { results[i] = true }
+	if slices.IsSortedFunc(values, intList.compare) {
+		intList.insertIndexed(slices.All(values), inserted)
+		return results
+	}
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	// stable, so that the first of equal values is inserted
+	slices.SortStableFunc(order, func(a, b int) int {
+		return intList.compare(values[a], values[b])
+	})
+	intList.insertIndexed(func(yield func(int, int) bool) {
+		for _, i := range order {
+			if !yield(i, values[i]) {
+				return
+			}
+		}
+	}, inserted)
+	return results
+}
+
+// Element is a position in a ConcurrentIntList, it lets callers walk the list with more
+// control than Range, e.g. looking at two consecutive values at once.
+type Element struct {
+	node *intNode
+}
+
+// nextLive returns the first unmarked node from n on.
+func nextLive(n *intNode) *intNode {
+	for n != nil && n.marked() {
+		n = n.next()
+	}
+	return n
+}
+
+// Front returns the first element of the list, or nil if the list is empty.
+func (intList *ConcurrentIntList) Front() *Element {
+	if n := nextLive(intList.root.next()); n != nil {
+		return &Element{node: n}
+	}
+	return nil
+}
+
+// Value returns the value held by the element.
+func (e *Element) Value() int {
+	return e.node.value
+}
+
+// Next returns the following element, or nil at the end of the list. Like Range it
+// skips deleted values and ignores concurrent modifications. An element that has been
+// deleted still leads to the rest of the list.
+func (e *Element) Next() *Element {
+	if n := nextLive(e.node.next()); n != nil {
+		return &Element{node: n}
+	}
+	return nil
+}
@@ -0,0 +1,535 @@
+package collections
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestInsertSeq(t *testing.T) {
+	l := NewConcurrentIntList()
+	for _, v := range []int{5, 15, 25} {
+		l.Insert(v)
+	}
+
+	// Generate 0, 3, 6, ..., 27 lazily, 15 is already present.
+	multiplesOf3 := func(yield func(int) bool) {
+		for v := 0; v < 30; v += 3 {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+	if n := l.InsertSeq(multiplesOf3); n != 9 {
+		t.Fatalf("invalid insert count expected %d, got %d", 9, n)
+	}
+	// 15 already present, out of order values fall back to a walk from the head.
+	if n := l.InsertSorted([]int{1, 15, 29, 2}); n != 3 {
+		t.Fatalf("invalid insert count expected %d, got %d", 3, n)
+	}
+
+	expected := []int{0, 1, 2, 3, 5, 6, 9, 12, 15, 18, 21, 24, 25, 27, 29}
+	if l.Len() != len(expected) || !l.IsSorted() {
+		t.Fatal("invalid length")
+	}
+	var i int
+	for v := range l.All() {
+		if v != expected[i] {
+			t.Fatalf("invalid value expected %d, got %d", expected[i], v)
+		}
+		i++
+	}
+
+	// Pipe one list into another.
+	d := NewDescendingIntList()
+	if n := d.InsertSeq(l.All()); n != len(expected) {
+		t.Fatalf("invalid insert count expected %d, got %d", len(expected), n)
+	}
+	next, stop := iter.Pull(d.All())
+	defer stop()
+	for i := len(expected) - 1; i >= 0; i-- {
+		if v, ok := next(); !ok || v != expected[i] {
+			t.Fatalf("invalid value expected %d, got %d", expected[i], v)
+		}
+	}
+}
+
+func TestInsertSeqAcrossRelinks(t *testing.T) {
+	// seq relinks the list between two inserts, the hint of the first one must not be reused
+	insertAround := func(l *ConcurrentIntList, relink func()) {
+		l.InsertSeq(func(yield func(int) bool) {
+			if yield(1) {
+				relink()
+				yield(5)
+			}
+		})
+	}
+
+	l := NewConcurrentIntList()
+	insertAround(l, func() { l.ReplaceAll([]int{0}) })
+	if got := l.ToSlice(); !slices.Equal(got, []int{0, 5}) || l.Len() != 2 || l.HealthCheck() != nil {
+		t.Fatalf("invalid insert across replace all %v, length %d", got, l.Len())
+	}
+
+	a, b := NewConcurrentIntList(), newListOf(3)
+	insertAround(a, func() { Swap(a, b) })
+	if got := a.ToSlice(); !slices.Equal(got, []int{3, 5}) || a.Len() != 2 || a.HealthCheck() != nil {
+		t.Fatalf("invalid insert across swap %v, length %d", got, a.Len())
+	}
+	if got := b.ToSlice(); !slices.Equal(got, []int{1}) || b.Len() != 1 || b.HealthCheck() != nil {
+		t.Fatalf("invalid swapped list %v, length %d", got, b.Len())
+	}
+}
+
+func TestBackward(t *testing.T) {
+	l := newListOf(3, 1, 4, 5, 9, 2, 6)
+	var got []int
+	for v := range l.Backward() {
+		got = append(got, v)
+	}
+	if !slices.Equal(got, []int{9, 6, 5, 4, 3, 2, 1}) {
+		t.Fatalf("invalid backward values %v", got)
+	}
+
+	// break stops the emission
+	got = got[:0]
+	for v := range l.Backward() {
+		if v < 5 {
+			break
+		}
+		got = append(got, v)
+	}
+	if !slices.Equal(got, []int{9, 6, 5}) {
+		t.Fatalf("invalid values before break %v", got)
+	}
+
+	if got := slices.Collect(NewDescendingIntList().Backward()); len(got) != 0 {
+		t.Fatalf("invalid values of an empty list %v", got)
+	}
+	d := NewDescendingIntList()
+	d.InsertSorted([]int{3, 2, 1})
+	if got := slices.Collect(d.Backward()); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("invalid backward values of a descending list %v", got)
+	}
+}
+
+func TestInsertSortedDesc(t *testing.T) {
+	l := newListOf(4, 10)
+	if n := l.InsertSortedDesc([]int{12, 10, 10, 7, 4, 3, 3, -1}); n != 4 {
+		t.Fatalf("invalid insert count expected %d, got %d", 4, n)
+	}
+	if got := l.ToSlice(); !slices.Equal(got, []int{-1, 3, 4, 7, 10, 12}) || l.Len() != 6 {
+		t.Fatalf("invalid list %v", got)
+	}
+	if n := l.InsertSortedDesc(nil); n != 0 {
+		t.Fatal("invalid insert of no values")
+	}
+	// out of order values are still inserted
+	if n := l.InsertSortedDesc([]int{5, 20, 0}); n != 3 || !l.IsSorted() || l.Len() != 9 {
+		t.Fatal("invalid insert of unsorted values")
+	}
+}
+
+func TestRangeBatch(t *testing.T) {
+	l := NewConcurrentIntList()
+	for i := 0; i < 10; i++ {
+		l.Insert(i)
+	}
+
+	collect := func(batch int) [][]int {
+		var batches [][]int
+		l.RangeBatch(batch, func(values []int) bool {
+			batches = append(batches, append([]int(nil), values...))
+			return true
+		})
+		return batches
+	}
+
+	// Batch of 1.
+	batches := collect(1)
+	if len(batches) != 10 {
+		t.Fatal("invalid batch count")
+	}
+	for i, b := range batches {
+		if len(b) != 1 || b[0] != i {
+			t.Fatal("invalid batch")
+		}
+	}
+
+	// Partial final batch.
+	batches = collect(4)
+	if len(batches) != 3 || len(batches[0]) != 4 || len(batches[1]) != 4 || len(batches[2]) != 2 {
+		t.Fatal("invalid batch count")
+	}
+	var expected int
+	for _, b := range batches {
+		for _, v := range b {
+			if v != expected {
+				t.Fatal("invalid batch")
+			}
+			expected++
+		}
+	}
+
+	// Batch greater than Len.
+	batches = collect(100)
+	if len(batches) != 1 || len(batches[0]) != 10 {
+		t.Fatal("invalid batch count")
+	}
+
+	// Stop early.
+	var calls int
+	l.RangeBatch(3, func(values []int) bool {
+		calls++
+		return false
+	})
+	if calls != 1 {
+		t.Fatal("invalid stop")
+	}
+
+	// Empty list.
+	NewConcurrentIntList().RangeBatch(3, func(values []int) bool {
+		t.Fatal("invalid batch on empty list")
+		return true
+	})
+}
+
+func benchmarkList(n int) *ConcurrentIntList {
+	l := NewConcurrentIntList()
+	values := make([]int, n)
+	for i := range values {
+		values[i] = i
+	}
+	l.InsertSorted(values)
+	return l
+}
+
+func BenchmarkRange(b *testing.B) {
+	l := benchmarkList(1e6)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var sum int
+		l.Range(func(value int) bool {
+			sum += value
+			return true
+		})
+	}
+}
+
+func BenchmarkRangeBatch(b *testing.B) {
+	l := benchmarkList(1e6)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var sum int
+		l.RangeBatch(256, func(values []int) bool {
+			for _, v := range values {
+				sum += v
+			}
+			return true
+		})
+	}
+}
+
+func benchmarkInsertDesc(b *testing.B, insert func(l *ConcurrentIntList, values []int)) {
+	evens, values := make([]int, 1e4), make([]int, 1e4)
+	for i := range values {
+		evens[i] = 2 * i
+		values[i] = 2*(len(values)-i) - 1
+	}
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		l := NewConcurrentIntList()
+		l.InsertSorted(evens)
+		b.StartTimer()
+		insert(l, values)
+	}
+}
+
+func BenchmarkInsertDesc(b *testing.B) {
+	benchmarkInsertDesc(b, func(l *ConcurrentIntList, values []int) {
+		for _, value := range values {
+			l.Insert(value)
+		}
+	})
+}
+
+func BenchmarkInsertSortedDesc(b *testing.B) {
+	benchmarkInsertDesc(b, func(l *ConcurrentIntList, values []int) {
+		l.InsertSortedDesc(values)
+	})
+}
+
+func TestElement(t *testing.T) {
+	l := NewConcurrentIntList()
+	if l.Front() != nil {
+		t.Fatal("invalid front of empty list")
+	}
+	for _, v := range []int{1, 3, 4, 10, 12} {
+		l.Insert(v)
+	}
+	l.Delete(4)
+
+	// Maximum gap between consecutive elements.
+	var maxGap int
+	for e := l.Front(); e != nil; e = e.Next() {
+		if next := e.Next(); next != nil && next.Value()-e.Value() > maxGap {
+			maxGap = next.Value() - e.Value()
+		}
+	}
+	if maxGap != 7 {
+		t.Fatalf("invalid max gap expected %d, got %d", 7, maxGap)
+	}
+
+	// An element deleted during the walk still leads to the rest.
+	e := l.Front().Next()
+	if e.Value() != 3 {
+		t.Fatal("invalid element")
+	}
+	l.Delete(3)
+	if next := e.Next(); next == nil || next.Value() != 10 {
+		t.Fatal("invalid next of a deleted element")
+	}
+}
+
+func TestRangeWhile(t *testing.T) {
+	l := NewConcurrentIntList()
+	for i := 1; i <= 100; i++ {
+		l.Insert(i)
+	}
+	var (
+		visits int
+		values []int
+	)
+	l.RangeWhile(func(value int) bool {
+		visits++
+		return value < 5
+	}, func(value int) bool {
+		values = append(values, value)
+		return true
+	})
+	if !slices.Equal(values, []int{1, 2, 3, 4}) {
+		t.Fatalf("invalid range while %v", values)
+	}
+	// the first value past the bound is the last one looked at
+	if visits != 5 {
+		t.Fatalf("invalid visits expected %d, got %d", 5, visits)
+	}
+
+	values = values[:0]
+	l.RangeWhile(func(value int) bool { return value < 50 }, func(value int) bool {
+		values = append(values, value)
+		return len(values) < 3
+	})
+	if !slices.Equal(values, []int{1, 2, 3}) {
+		t.Fatalf("invalid range while stopped by f %v", values)
+	}
+
+	visits = 0
+	l.RangeWhile(func(value int) bool {
+		visits++
+		return false
+	}, func(value int) bool {
+		t.Fatal("invalid call past the bound")
+		return true
+	})
+	if visits != 1 {
+		t.Fatalf("invalid visits expected %d, got %d", 1, visits)
+	}
+}
+
+func TestRangeWithLast(t *testing.T) {
+	l := NewConcurrentIntList()
+	l.RangeWithLast(func(int, bool) bool {
+		t.Fatal("invalid call on empty list")
+		return true
+	})
+
+	l.Insert(1)
+	var calls int
+	l.RangeWithLast(func(value int, isLast bool) bool {
+		calls++
+		if value != 1 || !isLast {
+			t.Fatalf("invalid single element %d, %v", value, isLast)
+		}
+		return true
+	})
+	if calls != 1 {
+		t.Fatalf("invalid calls expected %d, got %d", 1, calls)
+	}
+
+	for i := 2; i <= 10; i++ {
+		l.Insert(i)
+	}
+	// a marked tail node must not hide the real last element
+	tail := newIntNode(11)
+	tail.mark()
+	last := l.root.next()
+	for last.next() != nil {
+		last = last.next()
+	}
+	last.updateNext(tail)
+	var (
+		lasts  int
+		joined string
+	)
+	l.RangeWithLast(func(value int, isLast bool) bool {
+		joined += fmt.Sprint(value)
+		if isLast {
+			lasts++
+			if value != 10 {
+				t.Fatalf("invalid last element %d", value)
+			}
+		} else {
+			joined += ","
+		}
+		return true
+	})
+	if lasts != 1 || joined != "1,2,3,4,5,6,7,8,9,10" {
+		t.Fatalf("invalid join %q", joined)
+	}
+}
+
+func TestRangeSample(t *testing.T) {
+	l := NewConcurrentIntList()
+	for i := 1; i <= 10; i++ {
+		l.Insert(i)
+	}
+	sample := func(step int) []int {
+		var values []int
+		l.RangeSample(step, func(value int) bool {
+			values = append(values, value)
+			return true
+		})
+		return values
+	}
+
+	if got := sample(1); !slices.Equal(got, l.ToSlice()) {
+		t.Fatalf("invalid sample %v", got)
+	}
+	if got := sample(2); !slices.Equal(got, []int{2, 4, 6, 8, 10}) {
+		t.Fatalf("invalid sample %v", got)
+	}
+	if got := sample(3); !slices.Equal(got, []int{3, 6, 9}) {
+		t.Fatalf("invalid sample %v", got)
+	}
+	if got := sample(11); len(got) != 0 {
+		t.Fatalf("invalid sample %v", got)
+	}
+
+	var calls int
+	l.RangeSample(2, func(int) bool {
+		calls++
+		return calls < 2
+	})
+	if calls != 2 {
+		t.Fatal("invalid stop")
+	}
+}
+
+func TestInsertBatchResult(t *testing.T) {
+	l := newListOf(2, 4)
+	got := l.InsertBatchResult([]int{1, 2, 3, 3, 5})
+	if !slices.Equal(got, []bool{true, false, true, false, true}) {
+		t.Fatalf("invalid results of sorted batch %v", got)
+	}
+	got = l.InsertBatchResult([]int{9, 0, 4, 9, 7, 0, -1})
+	if !slices.Equal(got, []bool{true, true, false, false, true, false, true}) {
+		t.Fatalf("invalid results of unsorted batch %v", got)
+	}
+	if values := l.ToSlice(); !slices.Equal(values, []int{-1, 0, 1, 2, 3, 4, 5, 7, 9}) || l.Len() != 9 {
+		t.Fatalf("invalid list after batches %v", values)
+	}
+	if got := l.InsertBatchResult(nil); len(got) != 0 {
+		t.Fatalf("invalid results of empty batch %v", got)
+	}
+
+	// refused values are reported false
+	d := NewDescendingIntList(WithDomain(0, 10))
+	got = d.InsertBatchResult([]int{3, 20, 8, 3})
+	if !slices.Equal(got, []bool{true, false, true, false}) || !slices.Equal(d.ToSlice(), []int{8, 3}) {
+		t.Fatalf("invalid results on descending list %v, %v", got, d.ToSlice())
+	}
+}
+
+func TestIterBuffered(t *testing.T) {
+	l := NewConcurrentIntList()
+	for i := 0; i < 1000; i++ {
+		l.Insert(i)
+	}
+	var got []int
+	for value := range l.IterBuffered(context.Background(), 16) {
+		got = append(got, value)
+	}
+	if !slices.Equal(got, l.ToSlice()) {
+		t.Fatalf("invalid full walk, %d values", len(got))
+	}
+
+	for _, bufSize := range []int{-1, 0, 4} {
+		ctx, cancel := context.WithCancel(context.Background())
+		values := l.IterBuffered(ctx, bufSize)
+		for i := 0; i < 3; i++ {
+			if value := <-values; value != i {
+				t.Fatalf("invalid value expected %d, got %d", i, value)
+			}
+		}
+		cancel()
+		// the producer exits and closes the channel, leaving at most bufSize values
+		var wg sync.WaitGroup
+		var left int
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range values {
+				left++
+			}
+		}()
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("producer still running after cancel with buffer %d", bufSize)
+		}
+		if left > max(bufSize, 0)+1 {
+			t.Fatalf("invalid values left after cancel with buffer %d: %d", bufSize, left)
+		}
+	}
+}
+
+func TestIterBufferedClose(t *testing.T) {
+	l := NewConcurrentIntList()
+	for i := 0; i < 1000; i++ {
+		l.Insert(i)
+	}
+	values := l.IterBuffered(context.Background(), 4)
+	if value := <-values; value != 0 {
+		t.Fatalf("invalid value expected %d, got %d", 0, value)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// the producer blocked on the full buffer exits without the consumer cancelling
+	done := make(chan int)
+	go func() {
+		var left int
+		for range values {
+			left++
+		}
+		done <- left
+	}()
+	select {
+	case left := <-done:
+		if left > 4+1 {
+			t.Fatalf("invalid values left after close: %d", left)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("producer still running after close")
+	}
+}
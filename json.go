@@ -0,0 +1,38 @@
+package collections
+
+import (
+	"io"
+	"strconv"
+)
+
+// jsonChunk is the size of the buffer EncodeJSON fills before each write.
+const jsonChunk = 512
+
+// EncodeJSON writes the list to w as a JSON array in the list's order, streaming it from a Range
+// walk through a small fixed buffer, so memory stays flat whatever the size of the list. It
+// stops at the first write error and returns it, w then holds a truncated array.
+func (intList *ConcurrentIntList) EncodeJSON(w io.Writer) error {
+	var err error
+	buf := make([]byte, 0, jsonChunk)
+	buf = append(buf, '[')
+	first := true
+	intList.Range(func(value int) bool {
+		if !first {
+			buf = append(buf, ',')
+		}
+		first = false
+		buf = strconv.AppendInt(buf, int64(value), 10)
+		// flush while there is still room for a comma and the longest int
+		if len(buf) >= jsonChunk-24 {
+			_, err = w.Write(buf)
+			buf = buf[:0]
+		}
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	buf = append(buf, ']')
+	_, err = w.Write(buf)
+	return err
+}
@@ -0,0 +1,64 @@
+package collections
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// limitedWriter fails once more than n bytes have been written.
+type limitedWriter struct {
+	buf bytes.Buffer
+	n   int
+}
+
+var errLimit = errors.New("write limit reached")
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if w.buf.Len()+len(p) > w.n {
+		return 0, errLimit
+	}
+	return w.buf.Write(p)
+}
+
+func TestEncodeJSON(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewConcurrentIntList()
+	if err := l.EncodeJSON(&buf); err != nil || buf.String() != "[]" {
+		t.Fatalf("invalid empty array %q, %v", buf.String(), err)
+	}
+
+	l.Insert(-1)
+	buf.Reset()
+	if err := l.EncodeJSON(&buf); err != nil || buf.String() != "[-1]" {
+		t.Fatalf("invalid array %q, %v", buf.String(), err)
+	}
+
+	for i := 0; i < 1000; i++ {
+		l.Insert(i * 1000)
+	}
+	buf.Reset()
+	if err := l.EncodeJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var values []int
+	if err := json.Unmarshal(buf.Bytes(), &values); err != nil || !slices.Equal(values, l.ToSlice()) {
+		t.Fatalf("invalid array of %d values, %v", len(values), err)
+	}
+
+	// A failing writer aborts the walk and gets a truncated array.
+	w := &limitedWriter{n: 1000}
+	if err := l.EncodeJSON(w); !errors.Is(err, errLimit) {
+		t.Fatalf("invalid write error %v", err)
+	}
+	if out := w.buf.String(); !strings.HasPrefix(out, "[-1,0,1000,") || strings.HasSuffix(out, "]") {
+		t.Fatalf("invalid truncated array %q", out)
+	}
+	w = &limitedWriter{n: buf.Len() - 1}
+	if err := l.EncodeJSON(w); !errors.Is(err, errLimit) {
+		t.Fatalf("invalid error on the closing bracket %v", err)
+	}
+}
@@ -1,8 +1,11 @@
 package collections
 
 import (
+	"errors"
+	"math"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 type IntList interface {
@@ -22,6 +25,27 @@ type IntList interface {
 	Len() int
 }
 
+// ReadOnlyIntList 是有序链表的只读视图，底层数据与原链表共享
+type ReadOnlyIntList interface {
+	// 检查一个元素是否存在，如果存在则返回 true，否则返回 false
+	Contains(value int) bool
+
+	// 遍历此有序链表的所有元素，如果 f 返回 false，则停止遍历
+	Range(f func(value int) bool)
+
+	// 返回有序链表的元素个数
+	Len() int
+
+	// 按顺序返回有序链表所有元素的快照
+	ToSlice() []int
+}
+
+var (
+	_ IntList         = (*ConcurrentIntList)(nil)
+	_ ReadOnlyIntList = (*ConcurrentIntList)(nil)
+	_ ReadOnlyIntList = readOnlyIntList{}
+)
+
 type intNode struct {
 	value       int
 	nextPtr     atomic.Value
@@ -51,19 +75,131 @@ func newIntNode(value int) *intNode {
 	return &intNode{value: value}
 }
 
+// IntLess is the ascending order used by NewConcurrentIntList. A custom comparator should compare
+// with < or > like it does: a subtraction based one such as a-b < 0 overflows for values of large
+// magnitude and corrupts the order.
+func IntLess(a, b int) bool {
+	return a < b
+}
+
+func intGreater(a, b int) bool {
+	return a > b
+}
+
+// ErrDuplicate is returned when inserting a value that is already present.
+var ErrDuplicate = errors.New("collections: value already present")
+
+// testHookBeforeUnlink is called by Delete after marking a node and before unlinking it.
+var testHookBeforeUnlink func()
+
+// testHookBeforeLink is called by Insert with the predecessor locked, before linking the new node.
+var testHookBeforeLink func()
+
 // ConcurrentIntList
 type ConcurrentIntList struct {
+	// root is the head sentinel, it holds no value: walks start at root.next() and a node is
+	// only compared once it is known not to be root, so its -1 is never taken for a value
 	root *intNode
 	size int64
+	// version is bumped after every committed Insert or Delete
+	version uint64
+	// lenCache holds the lenCount of the last LenCached recount
+	lenCache atomic.Value
+	// less reports whether a must be placed before b, all ordering goes through it
+	less func(a, b int) bool
+
+	// gate is shared by writers and held exclusively while the list is frozen
+	gate       sync.RWMutex
+	freezeMu   sync.Mutex
+	frozen     int32
+	freezeMode FreezeMode
+	// gateWaiters counts the writes waiting in waitGate, the gate is only signaled for them
+	gateWaiters int32
+	gateMu      sync.Mutex
+	// gateFree is closed and dropped by signalGate, nil until a waiter asks for it
+	gateFree chan struct{}
+
+	closed int32
+	// done is closed by Close, for the goroutines serving the list
+	done chan struct{}
+
+	hasDomain            bool
+	domainMin, domainMax int
+	// canonicalizer is the WithCanonicalizer function, nil for none
+	canonicalizer func(value int) int
+	// equalPolicy is the WithEqualPolicy policy, if hasEqualPolicy
+	equalPolicy    EqualPolicy
+	hasEqualPolicy bool
+	// capacity is the maximum size, 0 means unbounded
+	capacity int64
+	// pool, if set, is the size shared by the shards of a ShardedIntList, capacity bounds it
+	pool *int64
+	// expectedSize is the WithExpectedSize hint, 0 when not given
+	expectedSize int
+	// seed is the WithSeed seed of structures built on the list, if hasSeed
+	seed    int64
+	hasSeed bool
+
+	// prefix holds the *prefixIndex set by BuildPrefixIndex
+	prefix atomic.Value
+	// relinks counts the exclusive writes moving nodes between chains, see keepHint
+	relinks uint64
+	// minWatch holds the WatchMin subscriptions
+	minWatch minWatch
+	// compactions counts the Compact runs, it closes the undo window of SoftDelete
+	compactions uint64
+	// maxRetries is the longest retry loop seen since construction or ResetMaxRetries
+	maxRetries int64
+
+	bloom        atomic.Value
+	bloomEnabled bool
+	bloomN       int
+	bloomFP      float64
+	bloomDeletes int64
 }
 
-func NewConcurrentIntList() *ConcurrentIntList {
-	return &ConcurrentIntList{root: newIntNode(-1)}
+// Option configures a ConcurrentIntList at construction.
+type Option func(intList *ConcurrentIntList)
+
+// newConcurrentIntList applies opts and validates the result, an invalid setting is replaced
+// by its default and reported in the returned error.
+func newConcurrentIntList(less func(a, b int) bool, opts []Option) (*ConcurrentIntList, error) {
+	intList := &ConcurrentIntList{root: newIntNode(-1), less: less, done: make(chan struct{})}
+	for _, opt := range opts {
+		opt(intList)
+	}
+	err := intList.validate()
+	if intList.bloomEnabled {
+		intList.bloomN = max(intList.bloomN, intList.expectedSize)
+		intList.bloom.Store(newBloomFilter(intList.bloomN, intList.bloomFP))
+	}
+	return intList, err
+}
+
+// NewConcurrentIntList never fails, invalid options fall back to their defaults, use
+// NewConcurrentIntListE to have them reported.
+func NewConcurrentIntList(opts ...Option) *ConcurrentIntList {
+	intList, _ := newConcurrentIntList(IntLess, opts)
+	return intList
 }
 
+// NewDescendingIntList returns a list ordered from the largest value to the smallest,
+// every ordered operation (Insert, Range, Min, Max) follows the reversed order.
+func NewDescendingIntList(opts ...Option) *ConcurrentIntList {
+	intList, _ := newConcurrentIntList(intGreater, opts)
+	return intList
+}
+
+// Contains reports whether value is present without taking any lock. It is linearizable with
+// the writes: it finds a value whose Insert returned and misses one whose Delete returned, while
+// a write of value is in flight it may answer either way.
 func (intList *ConcurrentIntList) Contains(value int) bool {
-	next := intList.root.next()
-	for next != nil && (next.marked() || next.value < value) {
+	value = intList.canon(value)
+	if bloom := intList.bloomFilter(); bloom != nil && !bloom.mayContain(value) {
+		return false
+	}
+	next := intList.walkStart(value).next()
+	for next != nil && (next.marked() || intList.before(next.value, value)) {
 		next = next.next()
 	}
 	if next == nil {
@@ -72,18 +208,142 @@ func (intList *ConcurrentIntList) Contains(value int) bool {
 	return next.value == value
 }
 
-func (intList *ConcurrentIntList) Insert(value int) bool {
+// ContainsStrict is Contains validated under the lock of the predecessor, like Insert does
+// before linking. A write of value holds that lock while it commits, so ContainsStrict waits for
+// an in-flight Insert or Delete of value and answers with its outcome, it never observes a
+// write halfway. It is slower than Contains and contends with the writers around value.
+func (intList *ConcurrentIntList) ContainsStrict(value int) bool {
+	value = intList.canon(value)
+	retries := -1
+	// observed once, with the final count, when the operation returns
+	defer func() { intList.observeRetries(retries) }()
 start:
+	retries++
 	pre := intList.root
 	current := pre.next()
-	// step1: find first node lager then value
-	for current != nil && current.value < value {
+	for current != nil && (current.marked() || intList.before(current.value, value)) {
+		pre = current
+		current = pre.next()
+	}
+	pre.mutex.Lock()
+	if pre.next() != current || pre.marked() || (current != nil && current.marked()) {
+		pre.mutex.Unlock()
+		goto start
+	}
+	present := current != nil && current.value == value
+	pre.mutex.Unlock()
+	return present
+}
+
+// ContainsZeroAllocProbe is Contains for hot membership probes, it allocates nothing and takes
+// no lock, and like every lookup it starts past the head sentinel, so any int, -1 included, is
+// only found once inserted.
+func (intList *ConcurrentIntList) ContainsZeroAllocProbe(value int) bool {
+	return intList.Contains(value)
+}
+
+func (intList *ConcurrentIntList) Insert(value int) bool {
+	inserted, _ := intList.TryInsert(value)
+	return inserted
+}
+
+// TryInsert is Insert reporting why a value was refused, err is ErrFrozen if the list is
+// frozen in FreezeReject mode, ErrClosed after Close, ErrFull once WithCapacity is reached and
+// ErrOutOfDomain for a value outside WithDomain. An already present value is not an error.
+func (intList *ConcurrentIntList) TryInsert(value int) (bool, error) {
+	switch err := intList.insert(value); err {
+	case nil:
+		return true, nil
+	case ErrDuplicate:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// InsertE inserts value and returns nil on success, or why it was not inserted: ErrDuplicate,
+// ErrFull, ErrOutOfDomain, ErrFrozen or ErrClosed. Insert is the bool shorthand of it.
+func (intList *ConcurrentIntList) InsertE(value int) error {
+	return intList.insert(value)
+}
+
+// SeenBefore inserts value and reports whether it was already present, !Insert(value), for
+// deduplicating idempotency keys: of concurrent callers with the same absent value exactly one
+// gets false. A value the list refuses is also reported as seen, use InsertE to tell them apart.
+func (intList *ConcurrentIntList) SeenBefore(value int) bool {
+	return !intList.Insert(value)
+}
+
+func (intList *ConcurrentIntList) insert(value int) error {
+	return intList.insertUntil(value, time.Time{})
+}
+
+// insertUntil gives up with ErrTimeout once deadline is passed, a zero deadline never expires.
+func (intList *ConcurrentIntList) insertUntil(value int, deadline time.Time) error {
+	value = intList.canon(value)
+	if !intList.inDomain(value) {
+		return ErrOutOfDomain
+	}
+	if err := intList.beginWrite(deadline); err != nil {
+		return err
+	}
+	defer intList.endWrite()
+	_, inserted, err := intList.insertAfter(intList.root, value, deadline, nil)
+	if err != nil {
+		return err
+	}
+	if !inserted {
+		return ErrDuplicate
+	}
+	return nil
+}
+
+// neighbors receives the nodes around the position of an inserted value, nil when it is first
+// or last.
+type neighbors struct {
+	pred, succ *intNode
+}
+
+// insertAfter inserts value searching from hint, hint must be root or a node ordered before value.
+// It returns the node holding value, which can be used as the hint of a following greater value,
+// and fills nb if it is not nil. The deadline is checked before every retry.
+func (intList *ConcurrentIntList) insertAfter(hint *intNode, value int, deadline time.Time, nb *neighbors) (*intNode, bool, error) {
+	retries := -1
+	// observed once, with the final count, when the operation returns
+	defer func() { intList.observeRetries(retries) }()
+start:
+	retries++
+	if retries > 0 && expired(deadline) {
+		return nil, false, ErrTimeout
+	}
+	if hint.marked() {
+		// hint has been deleted, its next pointer is no longer reliable
+		hint = intList.root
+	}
+	pre := hint
+	current := pre.next()
+	// step1: find first node lager then value, a deleted node with the same value must not be
+	// taken as present. A Delete is linearized when it marks the node, so an Insert that starts
+	// after the mark always inserts a fresh node: it walks past the marked one, waits on its lock
+	// as pre, fails the validation once the Delete unlinks it and retries from the new pre.
+	for current != nil && (current.marked() || intList.less(current.value, value) ||
+		(intList.equalPolicy == EqualAllow && intList.tied(current.value, value))) {
 		pre = current
 		current = pre.next()
 	}
 	// not find
-	if current != nil && current.value == value {
-		return false
+	if current != nil && (current.value == value || intList.tied(current.value, value)) {
+		if current.value != value && intList.equalPolicy == EqualReplace {
+			replaced, ok := intList.replaceAfter(pre, current, value, nb)
+			if !ok {
+				goto start
+			}
+			return replaced, true, nil
+		}
+		if nb != nil {
+			intList.fillNeighbors(nb, pre, current)
+		}
+		return current, false, nil
 	}
 	// step2: lock pre
 	pre.mutex.Lock()
@@ -92,29 +352,102 @@ start:
 		pre.mutex.Unlock()
 		goto start
 	}
-	// step4: add net node
+	// step4: add net node, the size is reserved under the lock so that it never exceeds capacity
+	if !intList.sizeIncr() {
+		pre.mutex.Unlock()
+		return nil, false, ErrFull
+	}
+	if bloom := intList.bloomFilter(); bloom != nil {
+		// before publishing, a visible node is never filtered out by Contains
+		bloom.add(value)
+	}
 	newNode := newIntNode(value)
 	// set next for new node first, avoid other goroutine get a invalid node
 	newNode.updateNext(current)
+	if testHookBeforeLink != nil {
+		testHookBeforeLink()
+	}
 	// add
-	intList.sizeIncr()
 	pre.updateNext(newNode)
+	if debugChecks {
+		intList.assertInserted(pre, newNode)
+	}
+	intList.versionIncr()
 	pre.mutex.Unlock()
-	return true
+	if nb != nil {
+		nb.succ = current
+		if pre != intList.root {
+			nb.pred = pre
+		}
+	}
+	return newNode, true, nil
 }
 
 func (intList *ConcurrentIntList) Delete(value int) bool {
+	deleted, _ := intList.TryDelete(value)
+	return deleted
+}
+
+// TryDelete is Delete reporting why a value was refused, see TryInsert.
+func (intList *ConcurrentIntList) TryDelete(value int) (bool, error) {
+	return intList.deleteUntil(value, time.Time{})
+}
+
+func (intList *ConcurrentIntList) deleteUntil(value int, deadline time.Time) (bool, error) {
+	_, deleted, err := intList.removeUntil(intList.canon(value), true, deadline)
+	return deleted, err
+}
+
+// ClaimSmallestAbove deletes and returns the first value not ordered before threshold, the
+// smallest value >= threshold for an ascending list. Finding and deleting the value is a single
+// Delete, so concurrent claimers never get the same value. ok is false if there is no such
+// value or the write was refused.
+func (intList *ConcurrentIntList) ClaimSmallestAbove(threshold int) (value int, ok bool) {
+	value, ok, _ = intList.removeUntil(threshold, false, time.Time{})
+	return value, ok
+}
+
+func (intList *ConcurrentIntList) removeUntil(value int, exact bool, deadline time.Time) (int, bool, error) {
+	if err := intList.beginWrite(deadline); err != nil {
+		return 0, false, err
+	}
+	_, removed, deleted, err := intList.removeAfter(intList.root, value, exact, deadline)
+	intList.endWrite()
+	if deleted {
+		// outside of the write, rebuilding waits for in-flight writes
+		intList.bloomDeleted()
+	}
+	return removed, deleted, err
+}
+
+// removeAfter deletes value, or if exact is false the first value not ordered before it, searching
+// from hint, and returns the deleted value. hint must be root or a node ordered before value, the
+// returned node precedes the position of value and can be used as the hint of a following greater
+// value. The deadline is checked before every retry.
+func (intList *ConcurrentIntList) removeAfter(hint *intNode, value int, exact bool, deadline time.Time) (*intNode, int, bool, error) {
+	retries := -1
+	// observed once, with the final count, when the operation returns
+	defer func() { intList.observeRetries(retries) }()
 start:
-	pre := intList.root
+	retries++
+	if retries > 0 && expired(deadline) {
+		return hint, 0, false, ErrTimeout
+	}
+	if hint.marked() {
+		// hint has been deleted, its next pointer is no longer reliable
+		hint = intList.root
+	}
+	pre := hint
 	current := pre.next()
-	// step1: find first node equal to value
-	for current != nil && (current.marked() || current.value < value) {
+	// step1: find first node not less than value, or value itself among its ties
+	for current != nil && (current.marked() || intList.less(current.value, value) ||
+		(exact && intList.tied(current.value, value))) {
 		pre = current
 		current = pre.next()
 	}
 	// not find
-	if current == nil || current.value != value {
-		return false
+	if current == nil || (exact && current.value != value) {
+		return pre, 0, false, nil
 	}
 	// step2: lock current
 	current.mutex.Lock()
@@ -134,31 +467,173 @@ start:
 	}
 	// step4: mark and remove
 	current.mark()
+	if testHookBeforeUnlink != nil {
+		testHookBeforeUnlink()
+	}
 	pre.updateNext(current.next())
 	intList.sizeDecr()
+	intList.versionIncr()
 	// anti flow, avoid dead lock
 	pre.mutex.Unlock()
 	current.mutex.Unlock()
-	return true
+	return pre, current.value, true, nil
 }
 
 func (intList *ConcurrentIntList) Range(f func(value int) bool) {
+	// we can't make sure list is not modified during range, so ignore the modify during range,
+	// but never report a node that has already been logically deleted.
+	var slow *intNode
+	for i, n := 0, intList.root.next(); n != nil; i, n = i+1, n.next() {
+		if debugChecks {
+			slow = intList.assertNoCycle(slow, n, i)
+		}
+		if !n.marked() && !f(n.value) {
+			return
+		}
+	}
+}
+
+// ToSlice returns the values in the list's order.
+func (intList *ConcurrentIntList) ToSlice() []int {
+	return intList.ToSliceInto(make([]int, 0, intList.Len()))
+}
+
+// ToSliceInto overwrites buf with the values in the list's order and returns it, growing it by
+// append if it is too small. The previous contents of buf are lost, callers reusing one buffer
+// for many snapshots must be done with a snapshot before taking the next.
+func (intList *ConcurrentIntList) ToSliceInto(buf []int) []int {
+	buf = buf[:0]
+	intList.Range(func(value int) bool {
+		buf = append(buf, value)
+		return true
+	})
+	return buf
+}
+
+// ToInt64Slice returns the values in the list's order widened to int64, for APIs taking []int64,
+// without an intermediate []int.
+func (intList *ConcurrentIntList) ToInt64Slice() []int64 {
+	values := make([]int64, 0, intList.Len())
+	intList.Range(func(value int) bool {
+		values = append(values, int64(value))
+		return true
+	})
+	return values
+}
+
+// readOnlyIntList hides the write methods, so the view can't be asserted back to IntList.
+type readOnlyIntList struct {
+	intList *ConcurrentIntList
+}
+
+func (r readOnlyIntList) Contains(value int) bool      { return r.intList.Contains(value) }
+func (r readOnlyIntList) Range(f func(value int) bool) { r.intList.Range(f) }
+func (r readOnlyIntList) Len() int                     { return r.intList.Len() }
+func (r readOnlyIntList) ToSlice() []int               { return r.intList.ToSlice() }
+
+// AsReadOnly returns a read-only view sharing the list's data, nothing is copied.
+func (intList *ConcurrentIntList) AsReadOnly() ReadOnlyIntList {
+	return readOnlyIntList{intList: intList}
+}
+
+// Min returns the first value in the list's order, for a descending list it is the largest one.
+// ok is false if the list is empty.
+func (intList *ConcurrentIntList) Min() (value int, ok bool) {
 	n := intList.root.next()
-	// we can't make sure list is not modified during range, so ignore the modify during range.
-	for n != nil && f(n.value) {
+	for n != nil && n.marked() {
 		n = n.next()
 	}
+	if n == nil {
+		return 0, false
+	}
+	return n.value, true
 }
 
-func (intList *ConcurrentIntList) sizeIncr() {
-	atomic.AddInt64(&intList.size, 1)
+// IsEmpty reports whether a walk finds no live value, it stops at the first one. Unlike
+// Len() == 0 it isn't fooled by deleted nodes still linked, which Len keeps counting.
+func (intList *ConcurrentIntList) IsEmpty() bool {
+	return nextLive(intList.root.next()) == nil
+}
+
+// Max returns the last value in the list's order, for a descending list it is the smallest one.
+// ok is false if the list is empty.
+func (intList *ConcurrentIntList) Max() (value int, ok bool) {
+	for n := intList.root.next(); n != nil; n = n.next() {
+		if !n.marked() {
+			value, ok = n.value, true
+		}
+	}
+	return value, ok
+}
+
+// IsSorted walks the list and reports whether every unmarked node is strictly ordered after
+// the previous one, a false result means the list has been corrupted by a concurrency bug.
+// It should be called while the list is quiescent.
+func (intList *ConcurrentIntList) IsSorted() bool {
+	var (
+		pre    int
+		hasPre bool
+	)
+	for n := intList.root.next(); n != nil; n = n.next() {
+		if n.marked() {
+			continue
+		}
+		if hasPre && !intList.before(pre, n.value) {
+			return false
+		}
+		pre, hasPre = n.value, true
+	}
+	return true
+}
+
+// sizeIncr returns false, without incrementing, if the list is full.
+func (intList *ConcurrentIntList) sizeIncr() bool {
+	if intList.capacity == 0 {
+		atomic.AddInt64(&intList.size, 1)
+		return true
+	}
+	counter := &intList.size
+	if intList.pool != nil {
+		counter = intList.pool
+	}
+	for {
+		size := atomic.LoadInt64(counter)
+		if size >= intList.capacity {
+			return false
+		}
+		if atomic.CompareAndSwapInt64(counter, size, size+1) {
+			break
+		}
+	}
+	if intList.pool != nil {
+		atomic.AddInt64(&intList.size, 1)
+	}
+	return true
 }
 
 func (intList *ConcurrentIntList) sizeDecr() {
 	atomic.AddInt64(&intList.size, -1)
+	if intList.pool != nil {
+		atomic.AddInt64(intList.pool, -1)
+	}
+}
+
+func (intList *ConcurrentIntList) versionIncr() {
+	atomic.AddUint64(&intList.version, 1)
+	intList.notifyMin()
 }
 
-// Len doesn't make sense in concurrent
+// Len doesn't make sense in concurrent.
+// On 32-bit platforms it saturates at math.MaxInt, use Len64 for the untruncated count.
 func (intList *ConcurrentIntList) Len() int {
-	return int(atomic.LoadInt64(&intList.size))
+	size := intList.Len64()
+	if size > math.MaxInt {
+		return math.MaxInt
+	}
+	return int(size)
+}
+
+// Len64 returns the element count without truncation.
+func (intList *ConcurrentIntList) Len64() int64 {
+	return atomic.LoadInt64(&intList.size)
 }
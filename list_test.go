@@ -2,9 +2,11 @@ package collections
 
 import (
 	"fmt"
+	"math"
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	_ "unsafe" // for linkname
 )
@@ -245,3 +247,601 @@ func TestIntSet(t *testing.T) {
 		panic("invalid count")
 	}
 }
+
+func TestDescendingIntList(t *testing.T) {
+	l := NewDescendingIntList()
+	if _, ok := l.Min(); ok {
+		t.Fatal("invalid min")
+	}
+	if _, ok := l.Max(); ok {
+		t.Fatal("invalid max")
+	}
+
+	for _, v := range []int{3, 1, 4, 5, 9, 2, 6} {
+		if !l.Insert(v) {
+			t.Fatal("invalid insert")
+		}
+	}
+	if l.Insert(4) || l.Len() != 7 {
+		t.Fatal("invalid insert")
+	}
+
+	expected := []int{9, 6, 5, 4, 3, 2, 1}
+	var i int
+	l.Range(func(value int) bool {
+		if value != expected[i] {
+			t.Fatalf("invalid range expected %d, got %d", expected[i], value)
+		}
+		i++
+		return true
+	})
+	if i != len(expected) {
+		t.Fatal("invalid range")
+	}
+
+	if v, ok := l.Min(); !ok || v != 9 {
+		t.Fatalf("invalid min expected %d, got %d", 9, v)
+	}
+	if v, ok := l.Max(); !ok || v != 1 {
+		t.Fatalf("invalid max expected %d, got %d", 1, v)
+	}
+
+	if !l.Contains(5) || l.Contains(7) {
+		t.Fatal("invalid contains")
+	}
+	if !l.Delete(9) || !l.Delete(1) || l.Delete(7) || l.Len() != 5 {
+		t.Fatal("invalid delete")
+	}
+	if v, ok := l.Min(); !ok || v != 6 {
+		t.Fatalf("invalid min expected %d, got %d", 6, v)
+	}
+	if v, ok := l.Max(); !ok || v != 2 {
+		t.Fatalf("invalid max expected %d, got %d", 2, v)
+	}
+}
+
+func TestIsSorted(t *testing.T) {
+	l := NewConcurrentIntList()
+	if !l.IsSorted() {
+		t.Fatal("invalid sorted")
+	}
+	for i := 0; i < 100; i++ {
+		l.Insert(int(fastrandn(1000)))
+	}
+	if !l.IsSorted() {
+		t.Fatal("invalid sorted")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			for i := 0; i < 1000; i++ {
+				if fastrandn(2) == 0 {
+					l.Delete(int(fastrandn(100)))
+				} else {
+					l.Insert(int(fastrandn(100)))
+				}
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+	if !l.IsSorted() {
+		t.Fatal("invalid sorted after concurrent mutations")
+	}
+
+	// Corrupt the order by hand.
+	n := l.root.next()
+	n.value, n.next().value = n.next().value, n.value
+	if l.IsSorted() {
+		t.Fatal("corrupted list reported as sorted")
+	}
+
+	d := NewDescendingIntList()
+	for _, v := range []int{1, 3, 2} {
+		d.Insert(v)
+	}
+	if !d.IsSorted() {
+		t.Fatal("invalid sorted")
+	}
+}
+
+func TestRangeSkipsMarked(t *testing.T) {
+	l := NewConcurrentIntList()
+	for i := 1; i <= 3; i++ {
+		l.Insert(i)
+	}
+
+	reached, release := make(chan struct{}), make(chan struct{})
+	testHookBeforeUnlink = func() {
+		close(reached)
+		<-release
+	}
+	defer func() { testHookBeforeUnlink = nil }()
+
+	done := make(chan struct{})
+	go func() {
+		if !l.Delete(2) {
+			panic("invalid delete")
+		}
+		close(done)
+	}()
+	// 2 is marked but still linked.
+	<-reached
+	if l.root.next().next().value != 2 {
+		t.Fatal("node unlinked before hook returned")
+	}
+	l.Range(func(value int) bool {
+		if value == 2 {
+			t.Fatal("range visits a deleted value")
+		}
+		return true
+	})
+	l.RangeBatch(2, func(values []int) bool {
+		for _, v := range values {
+			if v == 2 {
+				t.Fatal("range batch visits a deleted value")
+			}
+		}
+		return true
+	})
+	close(release)
+	<-done
+}
+
+func TestAsReadOnly(t *testing.T) {
+	l := NewConcurrentIntList()
+	l.Insert(2)
+	l.Insert(1)
+
+	r := l.AsReadOnly()
+	if _, ok := r.(IntList); ok {
+		t.Fatal("read only view exposes write methods")
+	}
+	if _, ok := r.(interface{ Insert(int) bool }); ok {
+		t.Fatal("read only view exposes insert")
+	}
+	if !r.Contains(1) || r.Len() != 2 {
+		t.Fatal("invalid read only view")
+	}
+
+	// The view shares data with the list.
+	l.Insert(3)
+	values := r.ToSlice()
+	if len(values) != 3 || values[0] != 1 || values[1] != 2 || values[2] != 3 {
+		t.Fatalf("invalid snapshot %v", values)
+	}
+	var count int
+	r.Range(func(int) bool {
+		count++
+		return true
+	})
+	if count != 3 {
+		t.Fatal("invalid range")
+	}
+}
+
+func TestLen64(t *testing.T) {
+	l := NewConcurrentIntList()
+	l.Insert(1)
+	if l.Len64() != 1 || l.Len() != 1 {
+		t.Fatal("invalid length")
+	}
+
+	// Fake a size that doesn't fit in a 32-bit int.
+	atomic.StoreInt64(&l.size, 1<<40)
+	if l.Len64() != 1<<40 {
+		t.Fatalf("invalid length expected %d, got %d", int64(1<<40), l.Len64())
+	}
+	if int64(l.Len()) != min(1<<40, math.MaxInt) {
+		t.Fatalf("invalid saturated length %d", l.Len())
+	}
+}
+
+func TestIsEmpty(t *testing.T) {
+	l := NewConcurrentIntList()
+	if !l.IsEmpty() {
+		t.Fatal("new list not empty")
+	}
+	l.Insert(1)
+	l.Insert(2)
+	if l.IsEmpty() {
+		t.Fatal("populated list empty")
+	}
+
+	// Only deleted nodes still linked, as Deletes that never got to unlink them.
+	for n := l.root.next(); n != nil; n = n.next() {
+		n.mark()
+	}
+	if !l.IsEmpty() || l.Len() != 2 {
+		t.Fatal("list of deleted nodes not empty")
+	}
+	// unlink them, an insert next to a deleted node waits for its Delete to unlink it
+	l.Compact()
+	l.Insert(3)
+	if l.IsEmpty() {
+		t.Fatal("list with a live node after deleted ones empty")
+	}
+	l.Delete(3)
+	if !l.IsEmpty() {
+		t.Fatal("list empty after delete not empty")
+	}
+}
+
+func TestToSliceInto(t *testing.T) {
+	l := NewConcurrentIntList()
+	for i := 0; i < 5; i++ {
+		l.Insert(i)
+	}
+	check := func(values []int) {
+		if len(values) != 5 {
+			t.Fatalf("invalid length %d", len(values))
+		}
+		for i, v := range values {
+			if v != i {
+				t.Fatalf("invalid values %v", values)
+			}
+		}
+	}
+
+	// Too small, exact and oversized buffers.
+	check(l.ToSliceInto(make([]int, 2)))
+	check(l.ToSliceInto(nil))
+	exact := make([]int, 0, 5)
+	values := l.ToSliceInto(exact)
+	check(values)
+	if &values[0] != &exact[:1][0] {
+		t.Fatal("exact buffer not reused")
+	}
+	oversized := []int{9, 9, 9, 9, 9, 9, 9, 9}
+	values = l.ToSliceInto(oversized)
+	check(values)
+	if &values[0] != &oversized[0] {
+		t.Fatal("oversized buffer not reused")
+	}
+}
+
+func TestToInt64Slice(t *testing.T) {
+	if got := NewConcurrentIntList().ToInt64Slice(); len(got) != 0 {
+		t.Fatalf("invalid values of an empty list %v", got)
+	}
+	for _, l := range []*ConcurrentIntList{newListOf(math.MinInt, -1, 0, 7, math.MaxInt), NewDescendingIntList()} {
+		l.Insert(3)
+		values, wide := l.ToSlice(), l.ToInt64Slice()
+		if len(wide) != len(values) {
+			t.Fatalf("invalid length expected %d, got %d", len(values), len(wide))
+		}
+		for i := range values {
+			if wide[i] != int64(values[i]) {
+				t.Fatalf("invalid values %v, expected %v", wide, values)
+			}
+		}
+	}
+}
+
+func BenchmarkToSlice(b *testing.B) {
+	l := benchmarkList(1e4)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.ToSlice()
+	}
+}
+
+func BenchmarkToSliceInto(b *testing.B) {
+	l := benchmarkList(1e4)
+	buf := make([]int, 0, l.Len())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = l.ToSliceInto(buf)
+	}
+}
+
+func TestInsertStorm(t *testing.T) {
+	l := NewConcurrentIntList()
+	const (
+		goroutines = 32
+		inserts    = 200
+		valueRange = 1024
+	)
+	var (
+		wg        sync.WaitGroup
+		reference sync.Map
+		inserted  int64
+	)
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			for i := 0; i < inserts; i++ {
+				v := int(fastrandn(valueRange))
+				reference.Store(v, true)
+				if l.Insert(v) {
+					atomic.AddInt64(&inserted, 1)
+				}
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	var union []int
+	reference.Range(func(key, _ any) bool {
+		union = append(union, key.(int))
+		return true
+	})
+	if !l.IsSorted() {
+		t.Fatal("invalid order after insert storm")
+	}
+	if l.Len() != len(union) || int(inserted) != len(union) {
+		t.Fatalf("invalid length expected %d, got %d with %d successful inserts", len(union), l.Len(), inserted)
+	}
+	for _, v := range union {
+		if !l.Contains(v) {
+			t.Fatalf("lost %d", v)
+		}
+	}
+	if n := len(l.ToSlice()); n != len(union) {
+		t.Fatalf("invalid walk length expected %d, got %d", len(union), n)
+	}
+}
+
+func TestInsertAfterMarkedDuplicate(t *testing.T) {
+	l := NewConcurrentIntList()
+	l.Insert(1)
+	l.Insert(2)
+
+	// Hold a delete of 2 between mark and unlink, a concurrent insert of 2 must not take the
+	// marked node as present.
+	reached, release := make(chan struct{}), make(chan struct{})
+	testHookBeforeUnlink = func() {
+		close(reached)
+		<-release
+	}
+	deleted := make(chan bool)
+	go func() {
+		deleted <- l.Delete(2)
+	}()
+	<-reached
+	testHookBeforeUnlink = nil
+	inserted := make(chan bool)
+	go func() {
+		inserted <- l.Insert(2)
+	}()
+	// let the insert reach the marked node
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	if !<-deleted || !<-inserted {
+		t.Fatal("insert took a deleted node as present")
+	}
+	if !l.Contains(2) || l.Len() != 2 || !l.IsSorted() {
+		t.Fatal("invalid list")
+	}
+}
+
+func TestReinsertJustDeleted(t *testing.T) {
+	const x = 5
+	l := NewConcurrentIntList()
+	for _, v := range []int{1, 3, 7, 9} {
+		l.Insert(v)
+	}
+
+	marked := make(chan struct{}, 1)
+	testHookBeforeUnlink = func() {
+		marked <- struct{}{}
+	}
+	defer func() { testHookBeforeUnlink = nil }()
+
+	for i := 0; i < 1000; i++ {
+		if !l.Insert(x) {
+			t.Fatal("invalid insert")
+		}
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			if !l.Delete(x) {
+				panic("invalid delete")
+			}
+			wg.Done()
+		}()
+		go func() {
+			// Inserting after the delete has marked the node must succeed.
+			<-marked
+			if !l.Insert(x) {
+				panic("insert took the deleted node as present")
+			}
+			wg.Done()
+		}()
+		wg.Wait()
+
+		var count int
+		l.Range(func(value int) bool {
+			if value == x {
+				count++
+			}
+			return true
+		})
+		if count != 1 || !l.Contains(x) || l.Len() != 5 {
+			t.Fatalf("%d is present %d times", x, count)
+		}
+		// Remove it for the next round, draining the hook signal.
+		l.Delete(x)
+		<-marked
+	}
+}
+
+func TestClaimSmallestAbove(t *testing.T) {
+	l := NewConcurrentIntList()
+	for i := 0; i < 10; i += 2 {
+		l.Insert(i)
+	}
+	if value, ok := l.ClaimSmallestAbove(3); !ok || value != 4 || l.Contains(4) || l.Len() != 4 {
+		t.Fatalf("invalid claim expected %d, got %d", 4, value)
+	}
+	if value, ok := l.ClaimSmallestAbove(2); !ok || value != 2 {
+		t.Fatalf("invalid claim expected %d, got %d", 2, value)
+	}
+	if _, ok := l.ClaimSmallestAbove(9); ok {
+		t.Fatal("invalid claim above the largest value")
+	}
+
+	// Many claimers never get the same value.
+	const n = 10000
+	l = NewConcurrentIntList()
+	for i := 0; i < n; i++ {
+		l.Insert(i)
+	}
+	var (
+		wg      sync.WaitGroup
+		claimed [n]int32
+	)
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			for {
+				value, ok := l.ClaimSmallestAbove(int(fastrandn(n)))
+				if !ok {
+					value, ok = l.ClaimSmallestAbove(0)
+				}
+				if !ok {
+					break
+				}
+				if value < 0 || value >= n {
+					panic(fmt.Sprintf("claimed %d which was never present", value))
+				}
+				if atomic.AddInt32(&claimed[value], 1) != 1 {
+					panic(fmt.Sprintf("%d claimed twice", value))
+				}
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+	for value, count := range claimed {
+		if count != 1 {
+			t.Fatalf("invalid claims of %d, expected %d, got %d", value, 1, count)
+		}
+	}
+	if l.Len() != 0 {
+		t.Fatal("invalid list after claiming every value")
+	}
+}
+
+func TestContainsStrict(t *testing.T) {
+	l := newListOf(1, 3)
+	if !l.ContainsStrict(1) || !l.ContainsStrict(3) || l.ContainsStrict(2) || l.ContainsStrict(4) {
+		t.Fatal("invalid strict contains")
+	}
+
+	// Pause an insert of 2 with its predecessor locked: Contains misses it, ContainsStrict
+	// waits for the insert and finds it.
+	linking, resume := make(chan struct{}), make(chan struct{})
+	testHookBeforeLink = func() {
+		linking <- struct{}{}
+		<-resume
+	}
+	defer func() { testHookBeforeLink = nil }()
+	go l.Insert(2)
+	<-linking
+	if l.Contains(2) {
+		t.Fatal("invalid contains of an insert not linked yet")
+	}
+	strict := make(chan bool)
+	go func() {
+		strict <- l.ContainsStrict(2)
+	}()
+	select {
+	case <-strict:
+		t.Fatal("invalid strict contains, expected it to wait for the insert")
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(resume)
+	if !<-strict {
+		t.Fatal("invalid strict contains after the insert")
+	}
+	testHookBeforeLink = nil
+
+	// Pause a delete of 2 before unlinking: ContainsStrict waits for it as well.
+	marked, resumeDelete := make(chan struct{}), make(chan struct{})
+	testHookBeforeUnlink = func() {
+		marked <- struct{}{}
+		<-resumeDelete
+	}
+	defer func() { testHookBeforeUnlink = nil }()
+	go l.Delete(2)
+	<-marked
+	go func() {
+		strict <- l.ContainsStrict(2)
+	}()
+	close(resumeDelete)
+	if <-strict || l.Contains(2) {
+		t.Fatal("invalid strict contains after the delete")
+	}
+}
+
+func TestSentinelBoundary(t *testing.T) {
+	for _, l := range []*ConcurrentIntList{NewConcurrentIntList(), NewDescendingIntList()} {
+		if l.Contains(-1) || l.ContainsZeroAllocProbe(-1) || l.ContainsStrict(-1) || l.Delete(-1) {
+			t.Fatal("invalid lookup of the sentinel value in an empty list")
+		}
+		if _, ok := l.Min(); ok || !l.IsEmpty() {
+			t.Fatal("invalid empty list")
+		}
+		for _, value := range []int{-1, math.MinInt, -2, 0, math.MaxInt} {
+			if !l.Insert(value) {
+				t.Fatalf("invalid insert of %d", value)
+			}
+		}
+		if l.Insert(-1) || l.Len() != 5 || l.HealthCheck() != nil {
+			t.Fatal("invalid duplicate of the sentinel value")
+		}
+		for _, value := range []int{-1, math.MinInt, -2, 0, math.MaxInt} {
+			if !l.Contains(value) || !l.ContainsZeroAllocProbe(value) || !l.ContainsStrict(value) {
+				t.Fatalf("invalid lookup of %d", value)
+			}
+		}
+		if l.Contains(-3) || l.Contains(1) {
+			t.Fatal("invalid lookup of an absent value")
+		}
+		// -1 is deleted and inserted again, at the head of an ascending list
+		if !l.Delete(math.MinInt) || !l.Delete(-1) || l.Contains(-1) || !l.Contains(-2) {
+			t.Fatal("invalid delete around the head")
+		}
+		if !l.Insert(-1) || !l.Contains(-1) || l.Len() != 4 {
+			t.Fatal("invalid insert at the head")
+		}
+		if n := testing.AllocsPerRun(100, func() {
+			l.ContainsZeroAllocProbe(-1)
+			l.ContainsZeroAllocProbe(1)
+		}); n != 0 {
+			t.Fatalf("invalid allocations of a probe %v", n)
+		}
+	}
+}
+
+func TestSeenBefore(t *testing.T) {
+	l := NewConcurrentIntList()
+	var first [100]int32
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range first {
+				if !l.SeenBefore(i) {
+					atomic.AddInt32(&first[i], 1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	for i, n := range first {
+		if n != 1 {
+			t.Fatalf("invalid first sightings of %d: %d", i, n)
+		}
+	}
+	if !l.SeenBefore(5) || l.SeenBefore(100) || l.Len() != 101 {
+		t.Fatal("invalid sighting after the concurrent ones")
+	}
+}
@@ -0,0 +1,81 @@
+package collections
+
+import (
+	"container/heap"
+	"slices"
+	"sync"
+)
+
+// sortedRuns is a min-heap of ascending runs ordered by their first value.
+type sortedRuns [][]int
+
+func (h sortedRuns) Len() int           { return len(h) }
+func (h sortedRuns) Less(i, j int) bool { return h[i][0] < h[j][0] }
+func (h sortedRuns) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *sortedRuns) Push(x any)        { *h = append(*h, x.([]int)) }
+func (h *sortedRuns) Pop() any {
+	old := *h
+	run := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return run
+}
+
+// next removes and returns the smallest first value, the heap must hold no empty run.
+func (h *sortedRuns) next() int {
+	value := (*h)[0][0]
+	if (*h)[0] = (*h)[0][1:]; len((*h)[0]) == 0 {
+		heap.Pop(h)
+	} else {
+		heap.Fix(h, 0)
+	}
+	return value
+}
+
+// LoadParallel returns an ascending list holding values, which may be unsorted and hold
+// duplicates. values is split into one chunk per worker, the chunks are sorted concurrently and
+// then merged straight into the chain of nodes, which needs no lock since the list is not shared
+// yet. values is not modified. workers lower than 1 means one.
+func LoadParallel(values []int, workers int) *ConcurrentIntList {
+	workers = max(min(workers, len(values)), 1)
+	sorted := slices.Clone(values)
+	runs := make(sortedRuns, 0, workers)
+	chunk := (len(sorted) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for lo := 0; lo < len(sorted); lo += chunk {
+		run := sorted[lo:min(lo+chunk, len(sorted))]
+		runs = append(runs, run)
+		wg.Add(1)
+		go func() {
+			slices.Sort(run)
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	intList := NewConcurrentIntList()
+	heap.Init(&runs)
+	tail := intList.root
+	for runs.Len() > 0 {
+		value := runs.next()
+		if tail != intList.root && tail.value == value {
+			continue
+		}
+		n := newIntNode(value)
+		tail.updateNext(n)
+		tail = n
+		intList.size++
+	}
+	return intList
+}
+
+// LoadFromSlice returns an ascending list holding values, which may be unsorted and hold
+// duplicates, like LoadParallel on a single goroutine: a sorted and deduplicated copy of values
+// is built into the chain in one pass. Unlike InsertSorted it assumes no order, and values is
+// not modified.
+func LoadFromSlice(values []int) *ConcurrentIntList {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	intList := NewConcurrentIntList()
+	intList.installChain(intList.dedupe(sorted))
+	return intList
+}
@@ -0,0 +1,68 @@
+package collections
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestLoadParallel(t *testing.T) {
+	values := make([]int, 10000)
+	for i := range values {
+		values[i] = int(fastrandn(5000)) - 2500
+	}
+	input := slices.Clone(values)
+	expected := slices.Compact(slices.Sorted(slices.Values(values)))
+	for _, workers := range []int{-1, 0, 1, 3, 8, 20000} {
+		l := LoadParallel(values, workers)
+		if got := l.ToSlice(); !slices.Equal(got, expected) || l.Len() != len(expected) {
+			t.Fatalf("invalid load with %d workers, %d values expected %d", workers, len(got), len(expected))
+		}
+		if l.HealthCheck() != nil || !l.Insert(10000) || l.Insert(expected[0]) {
+			t.Fatalf("invalid list loaded with %d workers", workers)
+		}
+	}
+	if !slices.Equal(values, input) {
+		t.Fatal("invalid load, the input was modified")
+	}
+	if l := LoadParallel(nil, 4); l.Len() != 0 || l.Front() != nil {
+		t.Fatal("invalid load of no values")
+	}
+}
+
+func TestLoadFromSlice(t *testing.T) {
+	reversed := []int{9, 7, 5, 3, 1, -1}
+	for _, c := range []struct {
+		values, want []int
+	}{
+		{reversed, []int{-1, 1, 3, 5, 7, 9}},
+		{[]int{4, 4, 2, 4, 2, 2, 8, 4}, []int{2, 4, 8}},
+		{nil, []int{}},
+		{[]int{}, []int{}},
+	} {
+		l := LoadFromSlice(c.values)
+		if got := l.ToSlice(); !slices.Equal(got, c.want) || l.Len() != len(c.want) {
+			t.Fatalf("invalid load of %v, got %v", c.values, got)
+		}
+		if l.HealthCheck() != nil || !l.IsSorted() || !l.Insert(100) {
+			t.Fatalf("invalid list loaded from %v", c.values)
+		}
+	}
+	if !slices.Equal(reversed, []int{9, 7, 5, 3, 1, -1}) {
+		t.Fatal("invalid load, the input was modified")
+	}
+}
+
+func BenchmarkLoadParallel(b *testing.B) {
+	values := make([]int, 1e6)
+	for i := range values {
+		values[i] = int(fastrand())
+	}
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				LoadParallel(values, workers)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package collections
+
+import (
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// Merge inserts every value of other into the list and returns how many were new. other is
+// snapshotted first, so it may be modified concurrently and may be the list itself.
+func (intList *ConcurrentIntList) Merge(other *ConcurrentIntList) int {
+	return intList.InsertSorted(other.ToSlice())
+}
+
+// Absorb moves every value of other into the list and leaves other empty. Unlike Merge it
+// allocates nothing: the nodes of other are spliced into the list in one merge walk, a node
+// whose value is already present, out of the domain or beyond the capacity is dropped.
+// The list itself may be used concurrently, Absorb runs as one exclusive write, but nobody else
+// may use other, not even to read, until Absorb returns. Like ReplaceAll it waits while the
+// list is frozen and does nothing, leaving other as is, if writes are refused.
+func (intList *ConcurrentIntList) Absorb(other *ConcurrentIntList) {
+	if other == intList {
+		return
+	}
+	intList.exclusiveWrite(func() {
+		other.relink(func() {
+			intList.absorb(other)
+		})
+		other.rebuildBloomFilter()
+	})
+}
+
+// absorb moves the nodes of other into the list, it must be called during an exclusive write.
+func (intList *ConcurrentIntList) absorb(other *ConcurrentIntList) {
+	var absorbed int64
+	bloom := intList.bloomFilter()
+	pre := intList.root
+	for n := other.root.next(); n != nil; {
+		next := n.next()
+		if n.marked() || !intList.inDomain(n.value) ||
+			(intList.capacity > 0 && atomic.LoadInt64(&intList.size)+absorbed >= intList.capacity) {
+			n = next
+			continue
+		}
+		// other may be ordered differently, restart the walk for a value out of order
+		if pre != intList.root && !intList.less(pre.value, n.value) {
+			pre = intList.root
+		}
+		// no write is in flight, so the list holds no marked node
+		current := pre.next()
+		for current != nil && intList.less(current.value, n.value) {
+			pre = current
+			current = pre.next()
+		}
+		if current != nil && current.value == n.value {
+			n = next
+			continue
+		}
+		if bloom != nil {
+			bloom.add(n.value)
+		}
+		// set next for the moved node first, like a fresh node in insertAfter
+		n.updateNext(current)
+		pre.updateNext(n)
+		if debugChecks {
+			intList.assertInserted(pre, n)
+		}
+		pre = n
+		absorbed++
+		n = next
+	}
+	if absorbed > 0 {
+		atomic.AddInt64(&intList.size, absorbed)
+		intList.versionIncr()
+	}
+	other.root.updateNext(nil)
+	atomic.StoreInt64(&other.size, 0)
+	other.versionIncr()
+}
+
+// snapshotOf returns the values of other in the list's order.
+func (intList *ConcurrentIntList) snapshotOf(other *ConcurrentIntList) []int {
+	values := other.ToSlice()
+	if !slices.IsSortedFunc(values, intList.compare) {
+		slices.SortFunc(values, intList.compare)
+	}
+	return values
+}
+
+// deleteSorted deletes values, which must follow the list's order, in a single forward walk and
+// returns how many were present. Each delete is a write of its own, values refused because the
+// list is frozen in FreezeReject mode or closed are skipped. onDelete, if not nil, is called
+// with every deleted value once its write is over.
+func (intList *ConcurrentIntList) deleteSorted(values []int, onDelete func(value int)) int {
+	var (
+		count   int
+		deleted bool
+		gen     uint64
+	)
+	hint := intList.root
+	for _, value := range values {
+		if intList.beginWrite(time.Time{}) != nil {
+			continue
+		}
+		// the hint comes from the previous write, the list may have been relinked since
+		hint, gen = intList.keepHint(hint, gen)
+		hint, _, deleted, _ = intList.removeAfter(hint, value, true, time.Time{})
+		intList.endWrite()
+		if deleted {
+			count++
+			intList.bloomDeleted()
+			if onDelete != nil {
+				onDelete(value)
+			}
+		}
+	}
+	return count
+}
+
+// DeleteRangeFunc deletes every value from lo to hi included, in the list's order, and returns
+// how many were removed. onDelete is called with each removed value, in order, once its delete
+// is over and no lock is held, so it may use the list. The values are those found by a walk
+// when DeleteRangeFunc starts, one inserted in the range meanwhile may survive, and one deleted
+// by someone else meanwhile is neither counted nor passed to onDelete.
+func (intList *ConcurrentIntList) DeleteRangeFunc(lo, hi int, onDelete func(value int)) int {
+	var values []int
+	for n := intList.walkStart(lo).next(); n != nil && !intList.less(hi, n.value); n = n.next() {
+		if !n.marked() && !intList.less(n.value, lo) {
+			values = append(values, n.value)
+		}
+	}
+	return intList.deleteSorted(values, onDelete)
+}
+
+// RemoveAllIn deletes every value of other from the list and returns how many were removed.
+// other is snapshotted first, then the list and the snapshot are merged in a single walk, so it
+// costs O(n+m) instead of a lookup per value. Each value is deleted as a separate write.
+func (intList *ConcurrentIntList) RemoveAllIn(other *ConcurrentIntList) int {
+	return intList.deleteSorted(intList.snapshotOf(other), nil)
+}
+
+// RetainAllIn deletes every value of the list that is not in other and returns how many were
+// removed, the in-place intersection. Both are snapshotted and merged in a single walk, values
+// inserted into the list meanwhile are kept.
+func (intList *ConcurrentIntList) RetainAllIn(other *ConcurrentIntList) int {
+	keep := intList.snapshotOf(other)
+	var drop []int
+	intList.mergeGroups(intList.ToSlice(), keep, func(values, kept []int) bool {
+		drop = appendMissing(drop, values, kept)
+		return true
+	})
+	return intList.deleteSorted(drop, nil)
+}
@@ -0,0 +1,270 @@
+package collections
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	a, b := NewConcurrentIntList(), NewDescendingIntList()
+	for i := 0; i < 10; i++ {
+		a.Insert(2 * i)
+		b.Insert(3 * i)
+	}
+	if n := a.Merge(b); n != 6 {
+		t.Fatalf("invalid merge count, expected %d, got %d", 6, n)
+	}
+	if a.Len() != 16 || !a.IsSorted() || b.Len() != 10 {
+		t.Fatal("invalid merge")
+	}
+	if n := a.Merge(a); n != 0 || a.Len() != 16 {
+		t.Fatal("invalid merge with itself")
+	}
+}
+
+func TestAbsorb(t *testing.T) {
+	a, b := NewConcurrentIntList(WithBloomFilter(64, 0.01)), NewConcurrentIntList(WithBloomFilter(64, 0.01))
+	for i := 0; i < 10; i++ {
+		a.Insert(2 * i)
+		b.Insert(3 * i)
+	}
+	va, vb := a.CurrentVersion(), b.CurrentVersion()
+	a.Absorb(b)
+	if a.Len() != 16 || !a.IsSorted() || a.CurrentVersion() == va {
+		t.Fatalf("invalid absorb %v", a.ToSlice())
+	}
+	for i := 0; i < 10; i++ {
+		if !a.Contains(2*i) || !a.Contains(3*i) {
+			t.Fatalf("invalid absorb, %d or %d missing", 2*i, 3*i)
+		}
+	}
+	if b.Len() != 0 || len(b.ToSlice()) != 0 || b.Contains(3) || b.CurrentVersion() == vb {
+		t.Fatal("invalid absorbed list, expected empty")
+	}
+	// other is reusable once emptied
+	b.Insert(100)
+	a.Absorb(b)
+	if !a.Contains(100) || a.Len() != 17 || b.Len() != 0 {
+		t.Fatal("invalid absorb of reused list")
+	}
+
+	a.Absorb(a)
+	if a.Len() != 17 {
+		t.Fatal("invalid absorb of itself")
+	}
+
+	// another order, the domain and the capacity
+	c := NewConcurrentIntList(WithDomain(0, 10), WithCapacity(4))
+	c.Insert(5)
+	d := NewDescendingIntList()
+	for i := -2; i <= 12; i++ {
+		d.Insert(i)
+	}
+	c.Absorb(d)
+	if got := c.ToSlice(); c.Len() != 4 || len(got) != 4 || got[0] != 5 || got[3] != 10 || !c.IsSorted() {
+		t.Fatalf("invalid absorb from descending list %v", got)
+	}
+	if d.Len() != 0 {
+		t.Fatal("invalid absorbed list, expected empty")
+	}
+}
+
+func TestAbsorbConcurrent(t *testing.T) {
+	l := NewConcurrentIntList()
+	for i := 0; i < 1000; i += 2 {
+		l.Insert(i)
+	}
+	var (
+		wg   sync.WaitGroup
+		stop int32
+	)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for atomic.LoadInt32(&stop) == 0 {
+				prev := -1
+				l.Range(func(value int) bool {
+					if value <= prev {
+						panic("invalid order during absorb")
+					}
+					prev = value
+					return true
+				})
+				l.Insert(int(fastrandn(1000)) | 1)
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		other := NewConcurrentIntList()
+		for j := 0; j < 10; j++ {
+			other.Insert(int(fastrandn(1000)))
+		}
+		l.Absorb(other)
+	}
+	atomic.StoreInt32(&stop, 1)
+	wg.Wait()
+	if l.Len() != len(l.ToSlice()) || !l.IsSorted() {
+		t.Fatal("invalid size after concurrent absorb")
+	}
+}
+
+func benchmarkUnion(b *testing.B, union func(l, other *ConcurrentIntList)) {
+	values := make([]int, 1e4)
+	for i := range values {
+		values[i] = 2 * i
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		l, other := benchmarkList(1e4), NewConcurrentIntList()
+		other.InsertSorted(values)
+		b.StartTimer()
+		union(l, other)
+	}
+}
+
+func BenchmarkMerge(b *testing.B) {
+	benchmarkUnion(b, func(l, other *ConcurrentIntList) { l.Merge(other) })
+}
+
+func BenchmarkAbsorb(b *testing.B) {
+	benchmarkUnion(b, func(l, other *ConcurrentIntList) { l.Absorb(other) })
+}
+
+func TestRemoveAllIn(t *testing.T) {
+	l := newListOf(1, 2, 3, 4, 5, 6)
+	if n := l.RemoveAllIn(newListOf(0, 2, 4, 7)); n != 2 || !slices.Equal(l.ToSlice(), []int{1, 3, 5, 6}) {
+		t.Fatalf("invalid remove of overlapping list %d, %v", n, l.ToSlice())
+	}
+	if n := l.RemoveAllIn(newListOf(10, 20)); n != 0 || l.Len() != 4 {
+		t.Fatalf("invalid remove of disjoint list %d", n)
+	}
+	d := NewDescendingIntList()
+	d.Insert(6)
+	d.Insert(1)
+	if n := l.RemoveAllIn(d); n != 2 || !slices.Equal(l.ToSlice(), []int{3, 5}) || !l.IsSorted() {
+		t.Fatalf("invalid remove of descending list %d, %v", n, l.ToSlice())
+	}
+	if n := l.RemoveAllIn(newListOf(3, 5)); n != 2 || l.Len() != 0 {
+		t.Fatalf("invalid remove of identical list %d", n)
+	}
+	l = newListOf(1, 2, 3)
+	if n := l.RemoveAllIn(l); n != 3 || l.Len() != 0 {
+		t.Fatalf("invalid remove of itself %d", n)
+	}
+}
+
+func TestRetainAllIn(t *testing.T) {
+	l := newListOf(1, 2, 3, 4, 5, 6)
+	if n := l.RetainAllIn(newListOf(0, 2, 4, 7)); n != 4 || !slices.Equal(l.ToSlice(), []int{2, 4}) {
+		t.Fatalf("invalid retain of partial overlap %d, %v", n, l.ToSlice())
+	}
+	if n := l.RetainAllIn(newListOf(2, 4)); n != 0 || !slices.Equal(l.ToSlice(), []int{2, 4}) {
+		t.Fatalf("invalid retain of identical list %d", n)
+	}
+	if n := l.RetainAllIn(l); n != 0 || l.Len() != 2 {
+		t.Fatalf("invalid retain of itself %d", n)
+	}
+	if n := l.RetainAllIn(NewConcurrentIntList()); n != 2 || l.Len() != 0 {
+		t.Fatalf("invalid retain of empty list %d", n)
+	}
+
+	d := NewDescendingIntList()
+	for _, value := range []int{9, 5, 3, 1} {
+		d.Insert(value)
+	}
+	if n := d.RetainAllIn(newListOf(1, 2, 9)); n != 2 || !slices.Equal(d.ToSlice(), []int{9, 1}) {
+		t.Fatalf("invalid retain on descending list %d, %v", n, d.ToSlice())
+	}
+}
+
+func TestDeleteRangeFunc(t *testing.T) {
+	l := newListOf(1, 3, 5, 7, 9, 11)
+	calls := make(map[int]int)
+	onDelete := func(value int) {
+		calls[value]++
+		// no lock is held, the list can be used
+		l.Contains(value)
+	}
+	if n := l.DeleteRangeFunc(2, 9, onDelete); n != 4 || !slices.Equal(l.ToSlice(), []int{1, 11}) {
+		t.Fatalf("invalid range delete %d, %v", n, l.ToSlice())
+	}
+	for _, value := range []int{3, 5, 7, 9} {
+		if calls[value] != 1 {
+			t.Fatalf("invalid callback count for %d: %d", value, calls[value])
+		}
+	}
+	if len(calls) != 4 {
+		t.Fatalf("callback fired for survivors %v", calls)
+	}
+	if n := l.DeleteRangeFunc(2, 9, onDelete); n != 0 || len(calls) != 4 {
+		t.Fatal("invalid delete of an empty range")
+	}
+	if n := l.DeleteRangeFunc(5, 2, nil); n != 0 || l.Len() != 2 {
+		t.Fatal("invalid delete of an inverted range")
+	}
+
+	// Concurrent deleters of overlapping ranges remove each value once.
+	l = NewConcurrentIntList()
+	values := make([]int, 1000)
+	for i := range values {
+		values[i] = i
+	}
+	l.InsertSorted(values)
+	var (
+		wg      sync.WaitGroup
+		fired   [1000]int32
+		removed int64
+	)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			n := l.DeleteRangeFunc(i*100, i*100+600, func(value int) {
+				atomic.AddInt32(&fired[value], 1)
+			})
+			atomic.AddInt64(&removed, int64(n))
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+	if removed != 901 || l.Len() != 99 {
+		t.Fatalf("invalid concurrent range delete %d removed, %d left", removed, l.Len())
+	}
+	for value, n := range fired {
+		want := int32(0)
+		if value <= 900 {
+			want = 1
+		}
+		if n != want {
+			t.Fatalf("invalid callback count for %d: %d", value, n)
+		}
+	}
+
+	d := NewDescendingIntList()
+	d.InsertSorted([]int{9, 7, 5, 3, 1})
+	if n := d.DeleteRangeFunc(7, 3, nil); n != 3 || !slices.Equal(d.ToSlice(), []int{9, 1}) {
+		t.Fatalf("invalid range delete on descending list %d, %v", n, d.ToSlice())
+	}
+}
+
+func TestDeleteSortedAcrossSwap(t *testing.T) {
+	// onDelete swaps the list with another, the next delete must not walk the other's chain
+	a, b := newListOf(0, 1, 2, 3), newListOf(0, 1, 2, 3)
+	swapped := false
+	n := a.DeleteRangeFunc(1, 3, func(int) {
+		if !swapped {
+			swapped = true
+			Swap(a, b)
+		}
+	})
+	// a holds b's values after the first delete, the rest are deleted from them
+	if got := a.ToSlice(); n != 3 || !slices.Equal(got, []int{0, 1}) || a.Len() != 2 || a.HealthCheck() != nil {
+		t.Fatalf("invalid delete across swap %d, %v", n, a.ToSlice())
+	}
+	if got := b.ToSlice(); !slices.Equal(got, []int{0, 2, 3}) || b.Len() != 3 || b.HealthCheck() != nil {
+		t.Fatalf("invalid swapped list %v, length %d", got, b.Len())
+	}
+}
@@ -0,0 +1,87 @@
+package collections
+
+// Move relocates from to the sorted position of to, it returns false if from is absent, to is
+// already present or out of the domain. Move is a single exclusive write, and the nodes between
+// the two positions are copied into a new segment that already holds to and lacks from, swapped
+// in with one pointer update: a concurrent reader walking past both positions sees either from
+// or to, never both nor neither. The replaced nodes are not marked, a reader standing on one must
+// still find its value, so writers keeping a hint across writes learn of the move from relink
+// instead. The copy costs a node per value between from and to.
+// Like ReplaceAll it waits while the list is frozen and does nothing if writes are refused.
+func (intList *ConcurrentIntList) Move(from, to int) bool {
+	from, to = intList.canon(from), intList.canon(to)
+	if from == to || !intList.inDomain(to) {
+		return false
+	}
+	var moved bool
+	intList.exclusiveWrite(func() {
+		intList.relink(func() {
+			moved = intList.move(from, to)
+		})
+	})
+	if moved {
+		// outside of the write, rebuilding waits for in-flight writes
+		intList.bloomDeleted()
+	}
+	return moved
+}
+
+// move must be called during an exclusive write, so the list holds no marked node.
+func (intList *ConcurrentIntList) move(from, to int) bool {
+	forward := intList.less(from, to)
+	first, last := from, to
+	if !forward {
+		first, last = to, from
+	}
+	// step1: find the node before the segment
+	pre := intList.root
+	for n := pre.next(); n != nil && intList.less(n.value, first); n = n.next() {
+		pre = n
+	}
+	// step2: copy the segment from first to last, leaving out from and adding to
+	var head, tail *intNode
+	add := func(value int) {
+		n := newIntNode(value)
+		if tail == nil {
+			head = n
+		} else {
+			tail.updateNext(n)
+		}
+		tail = n
+	}
+	n := pre.next()
+	if forward {
+		if n == nil || n.value != from {
+			return false
+		}
+		n = n.next()
+	} else {
+		if n != nil && n.value == to {
+			return false
+		}
+		add(to)
+	}
+	for n != nil && intList.less(n.value, last) {
+		add(n.value)
+		n = n.next()
+	}
+	if forward {
+		if n != nil && n.value == to {
+			return false
+		}
+		add(to)
+	} else {
+		if n == nil || n.value != from {
+			return false
+		}
+		n = n.next()
+	}
+	// step3: publish the segment at once
+	if bloom := intList.bloomFilter(); bloom != nil {
+		bloom.add(to)
+	}
+	tail.updateNext(n)
+	pre.updateNext(head)
+	intList.versionIncr()
+	return true
+}
@@ -0,0 +1,94 @@
+package collections
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMove(t *testing.T) {
+	l := newListOf(1, 3, 5, 7, 9)
+	v := l.CurrentVersion()
+	if !l.Move(3, 8) || !slices.Equal(l.ToSlice(), []int{1, 5, 7, 8, 9}) || l.Len() != 5 {
+		t.Fatalf("invalid move forward %v", l.ToSlice())
+	}
+	if l.CurrentVersion() == v {
+		t.Fatal("invalid version after move")
+	}
+	if !l.Move(8, 2) || !slices.Equal(l.ToSlice(), []int{1, 2, 5, 7, 9}) {
+		t.Fatalf("invalid move backward %v", l.ToSlice())
+	}
+	if !l.Move(1, 0) || !l.Move(0, 100) || !slices.Equal(l.ToSlice(), []int{2, 5, 7, 9, 100}) {
+		t.Fatalf("invalid move to a boundary %v", l.ToSlice())
+	}
+	if !l.Move(100, -100) || !slices.Equal(l.ToSlice(), []int{-100, 2, 5, 7, 9}) {
+		t.Fatalf("invalid move across the list %v", l.ToSlice())
+	}
+	if l.Move(2, 5) || l.Move(9, -100) || l.Move(4, 6) || l.Move(5, 5) {
+		t.Fatal("invalid move colliding with a present value or from an absent one")
+	}
+	if !slices.Equal(l.ToSlice(), []int{-100, 2, 5, 7, 9}) || l.Len() != 5 || !l.IsSorted() {
+		t.Fatalf("invalid list after failed moves %v", l.ToSlice())
+	}
+
+	d := NewDescendingIntList(WithDomain(0, 10))
+	d.Insert(1)
+	d.Insert(5)
+	if !d.Move(1, 9) || !slices.Equal(d.ToSlice(), []int{9, 5}) || d.Move(9, 11) {
+		t.Fatalf("invalid move on descending list %v", d.ToSlice())
+	}
+}
+
+func TestMoveConcurrent(t *testing.T) {
+	// One value moves back and forth, every walk sees it exactly once.
+	l := NewConcurrentIntList()
+	for i := 0; i < 100; i += 2 {
+		l.Insert(i)
+	}
+	var (
+		wg   sync.WaitGroup
+		stop int32
+	)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for atomic.LoadInt32(&stop) == 0 {
+				var odd int
+				l.Range(func(value int) bool {
+					if value%2 == 1 {
+						odd++
+					}
+					return true
+				})
+				if odd != 1 {
+					panic("invalid walk during move")
+				}
+			}
+		}()
+	}
+	l.Insert(1)
+	for i := 0; i < 1000; i++ {
+		if !l.Move(1, 99) || !l.Move(99, 1) {
+			t.Fatal("invalid move")
+		}
+	}
+	atomic.StoreInt32(&stop, 1)
+	wg.Wait()
+}
+
+func TestMoveDropsHints(t *testing.T) {
+	// the copied-out nodes are left unmarked, so that a reader standing on one still finds its
+	// value, but a writer must not reuse one of them as a hint
+	l := newListOf(1, 3)
+	l.InsertSeq(func(yield func(int) bool) {
+		if yield(2) {
+			l.Move(3, 0)
+			yield(5)
+		}
+	})
+	if got := l.ToSlice(); !slices.Equal(got, []int{0, 1, 2, 5}) || l.Len() != 4 || l.HealthCheck() != nil {
+		t.Fatalf("invalid insert across move %v, length %d", got, l.Len())
+	}
+}
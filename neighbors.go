@@ -0,0 +1,76 @@
+package collections
+
+import "time"
+
+// Surrounding reports in a single walk whether value is present and the values immediately
+// before and after it in the list's order, skipping deleted nodes like Contains.
+func (intList *ConcurrentIntList) Surrounding(value int) (pred int, hasPred bool, succ int, hasSucc bool, present bool) {
+	for n := intList.root.next(); n != nil; n = n.next() {
+		switch {
+		case n.marked():
+		case present:
+			return pred, hasPred, n.value, true, present
+		case n.value == value:
+			present = true
+		case intList.before(n.value, value):
+			// values tied with an absent value are all before its position, like for Insert
+			pred, hasPred = n.value, true
+		default:
+			return pred, hasPred, n.value, true, present
+		}
+	}
+	return pred, hasPred, succ, hasSucc, present
+}
+
+// EstimatedInsertCost returns how many nodes an Insert of value would walk past before reaching
+// its position, deleted nodes not yet unlinked included, without inserting. It is the rank of
+// value on a quiescent list and a cheap hint on how long writes around value hold the walk,
+// e.g. to decide whether to shard.
+func (intList *ConcurrentIntList) EstimatedInsertCost(value int) int {
+	var cost int
+	for n := intList.root.next(); n != nil && (n.marked() || intList.less(n.value, value)); n = n.next() {
+		cost++
+	}
+	return cost
+}
+
+// InsertWithNeighbors inserts value and reports the values immediately before and after the
+// position it was inserted at, as seen by the insert when linking it. If value is already present
+// inserted is false and the neighbors are those of the present value. Like Insert it returns false
+// without neighbors for a value that is refused.
+func (intList *ConcurrentIntList) InsertWithNeighbors(value int) (inserted bool, pred int, hasPred bool, succ int, hasSucc bool) {
+	value = intList.canon(value)
+	if !intList.inDomain(value) || intList.beginWrite(time.Time{}) != nil {
+		return false, 0, false, 0, false
+	}
+	var nb neighbors
+	_, inserted, err := intList.insertAfter(intList.root, value, time.Time{}, &nb)
+	intList.endWrite()
+	if err != nil {
+		return false, 0, false, 0, false
+	}
+	if nb.pred != nil {
+		pred, hasPred = nb.pred.value, true
+	}
+	if nb.succ != nil {
+		succ, hasSucc = nb.succ.value, true
+	}
+	return inserted, pred, hasPred, succ, hasSucc
+}
+
+// fillNeighbors sets nb to the live nodes around n, found after pre by a walk.
+func (intList *ConcurrentIntList) fillNeighbors(nb *neighbors, pre, n *intNode) {
+	if pre.marked() {
+		// pre is being deleted, look for the live predecessor from the head
+		pre = intList.root
+		for next := pre.next(); next != nil && next != n && intList.less(next.value, n.value); next = next.next() {
+			if !next.marked() {
+				pre = next
+			}
+		}
+	}
+	if pre != intList.root {
+		nb.pred = pre
+	}
+	nb.succ = nextLive(n.next())
+}
@@ -0,0 +1,104 @@
+package collections
+
+import "testing"
+
+func TestSurrounding(t *testing.T) {
+	l := NewConcurrentIntList()
+	for _, v := range []int{10, 20, 30, 40} {
+		l.Insert(v)
+	}
+	l.Delete(30)
+
+	check := func(value, pred int, hasPred bool, succ int, hasSucc, present bool) {
+		t.Helper()
+		p, hp, s, hs, ok := l.Surrounding(value)
+		if p != pred || hp != hasPred || s != succ || hs != hasSucc || ok != present {
+			t.Fatalf("invalid surrounding of %d: %d %v %d %v %v", value, p, hp, s, hs, ok)
+		}
+	}
+	// Present.
+	check(20, 10, true, 40, true, true)
+	// Absent between two, the deleted 30 is skipped.
+	check(30, 20, true, 40, true, false)
+	check(15, 10, true, 20, true, false)
+	// Below min and above max.
+	check(5, 0, false, 10, true, false)
+	check(10, 0, false, 20, true, true)
+	check(50, 40, true, 0, false, false)
+	check(40, 20, true, 0, false, true)
+
+	// Neighbors follow the list's order.
+	d := NewDescendingIntList()
+	d.InsertSeq(l.All())
+	if p, hp, s, hs, ok := d.Surrounding(25); p != 40 || !hp || s != 20 || !hs || ok {
+		t.Fatal("invalid surrounding in descending list")
+	}
+
+	if _, hp, _, hs, ok := NewConcurrentIntList().Surrounding(1); hp || hs || ok {
+		t.Fatal("invalid surrounding in empty list")
+	}
+}
+
+func TestEstimatedInsertCost(t *testing.T) {
+	l := NewConcurrentIntList()
+	if cost := l.EstimatedInsertCost(1); cost != 0 {
+		t.Fatalf("invalid cost on empty list, expected %d, got %d", 0, cost)
+	}
+	for i := 0; i < 100; i += 10 {
+		l.Insert(i)
+	}
+	for _, value := range []int{-1, 0, 5, 10, 55, 90, 1000} {
+		var walked int
+		for n := l.root.next(); n != nil && n.value < value; n = n.next() {
+			walked++
+		}
+		if cost := l.EstimatedInsertCost(value); cost != walked {
+			t.Fatalf("invalid cost of %d, expected %d, got %d", value, walked, cost)
+		}
+	}
+	if cost := l.EstimatedInsertCost(55); l.Len() != 10 || cost != 6 {
+		t.Fatal("invalid estimate, the list must not be modified")
+	}
+
+	d := NewDescendingIntList()
+	d.Insert(1)
+	d.Insert(3)
+	if cost := d.EstimatedInsertCost(2); cost != 1 {
+		t.Fatalf("invalid cost on descending list, expected %d, got %d", 1, cost)
+	}
+}
+
+func TestInsertWithNeighbors(t *testing.T) {
+	l := NewConcurrentIntList(WithDomain(0, 100))
+	check := func(value int, inserted bool, pred int, hasPred bool, succ int, hasSucc bool) {
+		t.Helper()
+		gotInserted, gotPred, gotHasPred, gotSucc, gotHasSucc := l.InsertWithNeighbors(value)
+		if gotInserted != inserted || gotPred != pred || gotHasPred != hasPred || gotSucc != succ || gotHasSucc != hasSucc {
+			t.Fatalf("invalid insert of %d: %v, %d, %v, %d, %v", value, gotInserted, gotPred, gotHasPred, gotSucc, gotHasSucc)
+		}
+	}
+	check(50, true, 0, false, 0, false)
+	check(10, true, 0, false, 50, true)
+	check(90, true, 50, true, 0, false)
+	check(30, true, 10, true, 50, true)
+	check(5, true, 0, false, 10, true)
+	// already present, the neighbors of the present value
+	check(30, false, 10, true, 50, true)
+	check(90, false, 50, true, 0, false)
+	check(5, false, 0, false, 10, true)
+	// refused
+	check(200, false, 0, false, 0, false)
+	if l.Len() != 5 || !l.IsSorted() {
+		t.Fatal("invalid list after inserts with neighbors")
+	}
+
+	// A deleted predecessor is skipped.
+	l = NewConcurrentIntList()
+	l.Insert(1)
+	l.Insert(2)
+	l.Insert(3)
+	l.root.next().next().mark()
+	if inserted, pred, hasPred, _, _ := l.InsertWithNeighbors(3); inserted || !hasPred || pred != 1 {
+		t.Fatalf("invalid neighbors past a deleted node %d", pred)
+	}
+}
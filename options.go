@@ -0,0 +1,85 @@
+package collections
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidOption is wrapped by the errors NewConcurrentIntListE returns for a nonsensical option.
+var ErrInvalidOption = errors.New("collections: invalid option")
+
+// WithComparator orders the list by less instead of ascending order. less must be a strict
+// weak order in which only equal ints are equivalent, unless WithEqualPolicy is given, values
+// are still compared with == for presence.
+func WithComparator(less func(a, b int) bool) Option {
+	return func(intList *ConcurrentIntList) {
+		intList.less = less
+	}
+}
+
+// WithExpectedSize tells the constructor how many values the list is expected to hold, so it can
+// size its structures up front instead of growing them. A plain ConcurrentIntList has nothing to
+// pre-size and only uses it to size a Bloom filter for at least n values, NewShardedIntList
+// derives its shard count from it when none is given.
+func WithExpectedSize(n int) Option {
+	return func(intList *ConcurrentIntList) {
+		intList.expectedSize = n
+	}
+}
+
+// WithSeed fixes the seed of any randomness in the structure so that its layout is reproducible,
+// the default seed comes from the runtime's entropy. A ConcurrentIntList has no randomness and
+// ignores it, NewShardedIntList seeds its shard hash with it.
+func WithSeed(seed int64) Option {
+	return func(intList *ConcurrentIntList) {
+		intList.seed, intList.hasSeed = seed, true
+	}
+}
+
+// NewConcurrentIntListE is NewConcurrentIntList reporting invalid options instead of replacing
+// them by their defaults, every invalid option is listed in the returned error.
+func NewConcurrentIntListE(opts ...Option) (*ConcurrentIntList, error) {
+	intList, err := newConcurrentIntList(IntLess, opts)
+	if err != nil {
+		return nil, err
+	}
+	return intList, nil
+}
+
+// validate resets every invalid setting to its default and returns the joined reasons.
+func (intList *ConcurrentIntList) validate() error {
+	var errs []error
+	if intList.less == nil {
+		errs = append(errs, fmt.Errorf("%w: nil comparator", ErrInvalidOption))
+		intList.less = IntLess
+	}
+	if intList.capacity < 0 {
+		errs = append(errs, fmt.Errorf("%w: negative capacity %d", ErrInvalidOption, intList.capacity))
+		intList.capacity = 0
+	}
+	if intList.expectedSize < 0 {
+		errs = append(errs, fmt.Errorf("%w: negative expected size %d", ErrInvalidOption, intList.expectedSize))
+		intList.expectedSize = 0
+	}
+	if intList.hasDomain && intList.domainMin > intList.domainMax {
+		errs = append(errs, fmt.Errorf("%w: empty domain [%d, %d]", ErrInvalidOption, intList.domainMin, intList.domainMax))
+		intList.hasDomain = false
+	}
+	if intList.hasEqualPolicy && (intList.equalPolicy < EqualAllow || intList.equalPolicy > EqualReplace) {
+		errs = append(errs, fmt.Errorf("%w: unknown equal policy %d", ErrInvalidOption, intList.equalPolicy))
+		intList.hasEqualPolicy = false
+	}
+	if intList.freezeMode != FreezeBlock && intList.freezeMode != FreezeReject {
+		errs = append(errs, fmt.Errorf("%w: unknown freeze mode %d", ErrInvalidOption, intList.freezeMode))
+		intList.freezeMode = FreezeBlock
+	}
+	if intList.bloomEnabled && intList.bloomN < 1 {
+		errs = append(errs, fmt.Errorf("%w: bloom filter for %d values", ErrInvalidOption, intList.bloomN))
+		intList.bloomEnabled = false
+	}
+	if intList.bloomEnabled && !(intList.bloomFP > 0 && intList.bloomFP < 1) {
+		errs = append(errs, fmt.Errorf("%w: bloom filter false positive rate %v", ErrInvalidOption, intList.bloomFP))
+		intList.bloomEnabled = false
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,101 @@
+package collections
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestNewConcurrentIntListE(t *testing.T) {
+	l, err := NewConcurrentIntListE(WithCapacity(2), WithDomain(0, 10), WithBloomFilter(16, 0.01))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Insert(1)
+	l.Insert(2)
+	if l.Insert(3) || l.Insert(11) || !l.Contains(1) {
+		t.Fatal("invalid list built from valid options")
+	}
+
+	invalid := [][]Option{
+		{WithCapacity(-1)},
+		{WithComparator(nil)},
+		{WithDomain(10, 0)},
+		{WithFreezeMode(FreezeMode(7))},
+		{WithBloomFilter(0, 0.01)},
+		{WithBloomFilter(16, 0)},
+		{WithBloomFilter(16, 1)},
+		{WithCapacity(4), WithCapacity(-4)},
+		{WithExpectedSize(-1)},
+	}
+	for i, opts := range invalid {
+		l, err := NewConcurrentIntListE(opts...)
+		if l != nil || !errors.Is(err, ErrInvalidOption) {
+			t.Fatalf("invalid options %d accepted: %v, %v", i, l, err)
+		}
+	}
+
+	_, err = NewConcurrentIntListE(WithCapacity(-1), WithComparator(nil))
+	var joined interface{ Unwrap() []error }
+	if !errors.As(err, &joined) || len(joined.Unwrap()) != 2 {
+		t.Fatalf("invalid error for two invalid options %v", err)
+	}
+}
+
+func TestNewConcurrentIntListDefaults(t *testing.T) {
+	l := NewConcurrentIntList(WithCapacity(-1), WithComparator(nil), WithDomain(10, 0),
+		WithFreezeMode(FreezeMode(7)), WithBloomFilter(16, 2))
+	for i := 20; i >= 0; i-- {
+		if !l.Insert(i) {
+			t.Fatalf("invalid insert of %d into a list with default options", i)
+		}
+	}
+	if l.Len() != 21 || !l.IsSorted() || l.ToSlice()[0] != 0 || l.bloomFilter() != nil {
+		t.Fatal("invalid defaults")
+	}
+
+	d := NewConcurrentIntList(WithComparator(intGreater))
+	d.Insert(1)
+	d.Insert(2)
+	if min, _ := d.Min(); min != 2 {
+		t.Fatalf("invalid custom comparator, expected min %d, got %d", 2, min)
+	}
+}
+
+func TestWithExpectedSize(t *testing.T) {
+	l := NewConcurrentIntList(WithExpectedSize(1000))
+	l.Insert(1)
+	if !l.Contains(1) || l.bloomFilter() != nil {
+		t.Fatal("invalid list with expected size")
+	}
+	l = NewConcurrentIntList(WithExpectedSize(1000), WithBloomFilter(10, 0.01))
+	if l.bloomN != 1000 || l.bloomFilter().m < newBloomFilter(1000, 0.01).m {
+		t.Fatal("invalid bloom filter sized by expected size")
+	}
+}
+
+func TestNewShardedIntListE(t *testing.T) {
+	if s, err := NewShardedIntListE(0); s != nil || !errors.Is(err, ErrInvalidOption) {
+		t.Fatalf("invalid zero shards accepted: %v", err)
+	}
+	if s, err := NewShardedIntListE(4); err != nil || len(s.shards) != 4 {
+		t.Fatalf("invalid sharded list: %v", err)
+	}
+}
+
+func TestSubtractionComparator(t *testing.T) {
+	// 1-math.MinInt overflows to a negative value, so 1 sorts before math.MinInt
+	naive := NewConcurrentIntList(WithComparator(func(a, b int) bool { return a-b < 0 }))
+	l := NewConcurrentIntList(WithComparator(IntLess))
+	for _, value := range []int{1, math.MinInt, math.MaxInt} {
+		naive.Insert(value)
+		l.Insert(value)
+	}
+	if slices.IsSorted(naive.ToSlice()) {
+		t.Fatal("invalid subtraction comparator, expected a broken order")
+	}
+	if got := l.ToSlice(); !slices.IsSorted(got) || len(got) != 3 {
+		t.Fatalf("invalid order with IntLess %v", got)
+	}
+}
@@ -0,0 +1,64 @@
+package collections
+
+// ConcurrentOrderedList keeps items of any type sorted by an int key extracted from each item.
+// Two items with the same key are the same element, so Contains and Delete look items up by
+// key. It is backed by a ConcurrentIntMap and shares its lazy locking.
+type ConcurrentOrderedList[T any] struct {
+	key   func(item T) int
+	items *ConcurrentIntMap[T]
+}
+
+func NewConcurrentOrderedList[T any](key func(item T) int) *ConcurrentOrderedList[T] {
+	return &ConcurrentOrderedList[T]{key: key, items: NewConcurrentIntMap[T]()}
+}
+
+// Insert adds item, it returns false and keeps the stored item if one with the same key exists.
+func (l *ConcurrentOrderedList[T]) Insert(item T) bool {
+	inserted := false
+	l.items.Upsert(l.key(item), func(old T, existed bool) T {
+		if existed {
+			return old
+		}
+		inserted = true
+		return item
+	})
+	return inserted
+}
+
+// Contains reports whether an item with the same key as item is stored.
+func (l *ConcurrentOrderedList[T]) Contains(item T) bool {
+	_, ok := l.items.Load(l.key(item))
+	return ok
+}
+
+// Get returns the item stored for key.
+func (l *ConcurrentOrderedList[T]) Get(key int) (T, bool) {
+	return l.items.Load(key)
+}
+
+// Delete removes the item with the same key as item.
+func (l *ConcurrentOrderedList[T]) Delete(item T) bool {
+	return l.items.Delete(l.key(item))
+}
+
+// Range calls f for every item in ascending key order, stopping when f returns false.
+func (l *ConcurrentOrderedList[T]) Range(f func(item T) bool) {
+	l.items.Range(func(_ int, item T) bool {
+		return f(item)
+	})
+}
+
+// KeysInt64 returns the keys of the items in ascending order widened to int64, the counterpart
+// of ConcurrentIntList.ToInt64Slice.
+func (l *ConcurrentOrderedList[T]) KeysInt64() []int64 {
+	keys := make([]int64, 0, l.Len())
+	l.items.Range(func(key int, _ T) bool {
+		keys = append(keys, int64(key))
+		return true
+	})
+	return keys
+}
+
+func (l *ConcurrentOrderedList[T]) Len() int {
+	return l.items.Len()
+}
@@ -0,0 +1,70 @@
+package collections
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestConcurrentOrderedList(t *testing.T) {
+	type user struct {
+		ID   int
+		Name string
+	}
+	l := NewConcurrentOrderedList(func(u user) int { return u.ID })
+
+	for _, u := range []user{{3, "c"}, {1, "a"}, {2, "b"}} {
+		if !l.Insert(u) {
+			t.Fatal("invalid insert")
+		}
+	}
+	// Same key, different name: the stored item is kept.
+	if l.Insert(user{2, "other"}) || l.Len() != 3 {
+		t.Fatal("invalid duplicate insert")
+	}
+	if u, ok := l.Get(2); !ok || u.Name != "b" {
+		t.Fatalf("invalid get %+v", u)
+	}
+	if !l.Contains(user{ID: 1}) || l.Contains(user{ID: 4, Name: "a"}) {
+		t.Fatal("invalid contains")
+	}
+
+	var names string
+	l.Range(func(u user) bool {
+		names += u.Name
+		return true
+	})
+	if names != "abc" {
+		t.Fatalf("invalid order %q", names)
+	}
+
+	if !l.Delete(user{ID: 1}) || l.Delete(user{ID: 1}) || l.Len() != 2 {
+		t.Fatal("invalid delete")
+	}
+
+	// Concurrent inserts with colliding keys keep exactly one item per key.
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			l.Insert(user{ID: 10 + i%10, Name: "x"})
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+	if l.Len() != 12 {
+		t.Fatalf("invalid length %d", l.Len())
+	}
+	pre := -1
+	l.Range(func(u user) bool {
+		if u.ID <= pre {
+			t.Fatal("invalid order")
+		}
+		pre = u.ID
+		return true
+	})
+	keys := l.KeysInt64()
+	if len(keys) != l.Len() || keys[0] != 2 || keys[1] != 3 || keys[len(keys)-1] != 19 {
+		t.Fatalf("invalid keys %v", keys)
+	}
+}
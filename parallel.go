@@ -0,0 +1,56 @@
+package collections
+
+import "sync"
+
+// MapReduce snapshots l, splits the snapshot into workers contiguous segments and maps and
+// reduces each segment in its own goroutine before combining the segment results in order.
+// reducer must be associative and identity its neutral element, since the grouping of the
+// reductions depends on workers; it doesn't need to be commutative. It is a package function
+// because Go methods can't take type parameters.
+func MapReduce[R any](l *ConcurrentIntList, workers int, mapper func(value int) R, reducer func(a, b R) R, identity R) R {
+	values := l.ToSlice()
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(values) {
+		workers = max(len(values), 1)
+	}
+	results := make([]R, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		segment := values[w*len(values)/workers : (w+1)*len(values)/workers]
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			acc := identity
+			for _, value := range segment {
+				acc = reducer(acc, mapper(value))
+			}
+			results[w] = acc
+		}(w)
+	}
+	wg.Wait()
+	acc := identity
+	for _, r := range results {
+		acc = reducer(acc, r)
+	}
+	return acc
+}
+
+// RangePartition snapshots the list, splits the snapshot into parts contiguous segments like
+// MapReduce and calls f in order on segment index only, stopping when f returns false, so parts
+// goroutines can share a scan without a common cursor. parts lower than 1 is taken as 1 and an
+// index outside [0, parts) visits nothing. Every call takes its own snapshot, the segments of
+// concurrent calls cover the list exactly once only if no write happens meanwhile.
+func (intList *ConcurrentIntList) RangePartition(parts, index int, f func(value int) bool) {
+	parts = max(parts, 1)
+	if index < 0 || index >= parts {
+		return
+	}
+	values := intList.ToSlice()
+	for _, value := range values[index*len(values)/parts : (index+1)*len(values)/parts] {
+		if !f(value) {
+			return
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package collections
+
+import (
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestMapReduce(t *testing.T) {
+	l := NewConcurrentIntList()
+	for i := 0; i < 1000; i++ {
+		l.Insert(int(fastrandn(1e6)))
+	}
+
+	add := func(a, b int) int { return a + b }
+	identity := func(v int) int { return v }
+	for _, workers := range []int{0, 1, 3, 8, 5000} {
+		if got := MapReduce(l, workers, identity, add, 0); got != l.Sum() {
+			t.Fatalf("invalid sum with %d workers expected %d, got %d", workers, l.Sum(), got)
+		}
+	}
+
+	// Associative but not commutative, segment order is kept.
+	s := NewConcurrentIntList()
+	for i := 0; i < 10; i++ {
+		s.Insert(i)
+	}
+	concat := MapReduce(s, 4, strconv.Itoa, func(a, b string) string { return a + b }, "")
+	if concat != "0123456789" {
+		t.Fatalf("invalid concat %q", concat)
+	}
+
+	if MapReduce(NewConcurrentIntList(), 4, identity, add, 0) != 0 {
+		t.Fatal("invalid sum of empty list")
+	}
+}
+
+func TestRangePartition(t *testing.T) {
+	l := NewConcurrentIntList()
+	for i := 0; i < 1003; i++ {
+		l.Insert(i * 3)
+	}
+	for _, parts := range []int{1, 4, 7, 2000} {
+		segments := make([][]int, parts)
+		var wg sync.WaitGroup
+		for index := range parts {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				l.RangePartition(parts, index, func(value int) bool {
+					segments[index] = append(segments[index], value)
+					return true
+				})
+			}()
+		}
+		wg.Wait()
+		if got := slices.Concat(segments...); !slices.Equal(got, l.ToSlice()) {
+			t.Fatalf("invalid concatenation of %d parts, %d values", parts, len(got))
+		}
+	}
+
+	var visited int
+	l.RangePartition(4, 4, func(int) bool { visited++; return true })
+	l.RangePartition(4, -1, func(int) bool { visited++; return true })
+	if visited != 0 {
+		t.Fatal("invalid walk of an out of range segment")
+	}
+	l.RangePartition(0, 0, func(int) bool { visited++; return visited < 10 })
+	if visited != 10 {
+		t.Fatalf("invalid early stop, visited %d", visited)
+	}
+}
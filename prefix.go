@@ -0,0 +1,78 @@
+package collections
+
+import (
+	"sort"
+	"sync/atomic"
+)
+
+// prefixIndex holds the first nodes of the list in order, it is never modified once stored.
+type prefixIndex struct {
+	n     int
+	nodes []*intNode
+}
+
+// BuildPrefixIndex captures the first n live nodes so that Contains can start its walk from the
+// last captured node before the value, found by binary search, instead of from the head. Values
+// past the prefix are looked up from its last node. It is a read optimization for a hot and
+// stable low end of the list: the index is a snapshot and goes stale as the list changes, call
+// BuildPrefixIndex again to refresh it, n lower than 1 drops it. A stale index is never wrong,
+// captured nodes deleted since are skipped and values inserted since are still found, it only
+// saves less of the walk.
+func (intList *ConcurrentIntList) BuildPrefixIndex(n int) {
+	index := &prefixIndex{n: n}
+	if n > 0 {
+		index.nodes = make([]*intNode, 0, min(n, intList.Len()))
+		for node := intList.root.next(); node != nil && len(index.nodes) < n; node = node.next() {
+			if !node.marked() {
+				index.nodes = append(index.nodes, node)
+			}
+		}
+	}
+	intList.prefix.Store(index)
+}
+
+// walkStart returns the node a read of value can start walking from: the last live indexed node
+// ordered before value, or the head.
+func (intList *ConcurrentIntList) walkStart(value int) *intNode {
+	index, _ := intList.prefix.Load().(*prefixIndex)
+	if index == nil {
+		return intList.root
+	}
+	nodes := index.nodes
+	i := sort.Search(len(nodes), func(i int) bool {
+		return !intList.less(nodes[i].value, value)
+	})
+	// a deleted node's next pointer may skip values inserted after it was unlinked
+	for i--; i >= 0; i-- {
+		if !nodes[i].marked() {
+			return nodes[i]
+		}
+	}
+	return intList.root
+}
+
+// relink runs f, which moves nodes to or from another chain, and then rebuilds the prefix index.
+// The index is dropped meanwhile so that a lookup never jumps from an indexed node into a chain
+// it no longer belongs to, and relinks is bumped so that hints kept across writes are dropped
+// too. It must be called during an exclusive write.
+func (intList *ConcurrentIntList) relink(f func()) {
+	atomic.AddUint64(&intList.relinks, 1)
+	index, _ := intList.prefix.Load().(*prefixIndex)
+	if index == nil || index.n < 1 {
+		f()
+		return
+	}
+	intList.prefix.Store(&prefixIndex{})
+	f()
+	intList.BuildPrefixIndex(index.n)
+}
+
+// keepHint returns hint, a node kept from an earlier write, if the list has not been relinked
+// since relinks was gen, and the head otherwise: a relink may have dropped the node or moved it
+// to another list, unmarked. It must be called during a write, gen comes from the previous call.
+func (intList *ConcurrentIntList) keepHint(hint *intNode, gen uint64) (*intNode, uint64) {
+	if current := atomic.LoadUint64(&intList.relinks); current != gen {
+		return intList.root, current
+	}
+	return hint, gen
+}
@@ -0,0 +1,98 @@
+package collections
+
+import "testing"
+
+func TestPrefixIndex(t *testing.T) {
+	l := NewConcurrentIntList()
+	for i := 0; i < 100; i += 2 {
+		l.Insert(i)
+	}
+	l.BuildPrefixIndex(10)
+	for i := -1; i < 101; i++ {
+		if l.Contains(i) != (i >= 0 && i < 100 && i%2 == 0) {
+			t.Fatalf("invalid contains %d with prefix index", i)
+		}
+	}
+
+	// Stale index: indexed nodes deleted, values inserted among and before them.
+	for i := 0; i < 10; i += 2 {
+		l.Delete(i)
+	}
+	for _, value := range []int{-5, 1, 3, 5, 7, 9, 11} {
+		l.Insert(value)
+	}
+	for _, value := range []int{-5, 1, 3, 5, 7, 9, 11, 12, 98} {
+		if !l.Contains(value) {
+			t.Fatalf("invalid contains %d with stale prefix index", value)
+		}
+	}
+	for _, value := range []int{0, 2, 4, 6, 8, 13, 99} {
+		if l.Contains(value) {
+			t.Fatalf("invalid contains %d with stale prefix index", value)
+		}
+	}
+
+	l.BuildPrefixIndex(1000)
+	if index := l.prefix.Load().(*prefixIndex); len(index.nodes) != l.Len() {
+		t.Fatalf("invalid prefix index of %d nodes, expected %d", len(index.nodes), l.Len())
+	}
+	l.BuildPrefixIndex(0)
+	if l.walkStart(50) != l.root || !l.Contains(50) {
+		t.Fatal("invalid dropped prefix index")
+	}
+
+	d := NewDescendingIntList()
+	for i := 0; i < 10; i++ {
+		d.Insert(i)
+	}
+	d.BuildPrefixIndex(5)
+	for i := 0; i < 10; i++ {
+		if !d.Contains(i) {
+			t.Fatalf("invalid contains %d with prefix index on descending list", i)
+		}
+	}
+}
+
+func TestPrefixIndexRelink(t *testing.T) {
+	a, b := NewConcurrentIntList(), NewConcurrentIntList()
+	for i := 0; i < 10; i++ {
+		a.Insert(i)
+		b.Insert(i + 100)
+	}
+	a.BuildPrefixIndex(5)
+	b.BuildPrefixIndex(5)
+	Swap(a, b)
+	if a.Contains(7) || !a.Contains(107) || !b.Contains(7) || b.Contains(107) {
+		t.Fatal("invalid contains with prefix index after swap")
+	}
+
+	a.ReplaceAll([]int{1, 2, 3})
+	if a.Contains(107) || !a.Contains(3) {
+		t.Fatal("invalid contains with prefix index after replace all")
+	}
+
+	b.Absorb(a)
+	if a.Contains(3) || !b.Contains(3) || !b.Contains(9) {
+		t.Fatal("invalid contains with prefix index after absorb")
+	}
+	if index := b.prefix.Load().(*prefixIndex); len(index.nodes) != 5 || index.nodes[0].value != 0 {
+		t.Fatal("invalid prefix index after absorb")
+	}
+}
+
+func benchmarkSmallLookups(b *testing.B, l *ConcurrentIntList) {
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Contains(1000 + i%1000)
+	}
+}
+
+func BenchmarkSmallLookups(b *testing.B) {
+	benchmarkSmallLookups(b, benchmarkList(1e4))
+}
+
+func BenchmarkSmallLookupsPrefixIndex(b *testing.B) {
+	l := benchmarkList(1e4)
+	l.BuildPrefixIndex(2000)
+	benchmarkSmallLookups(b, l)
+}
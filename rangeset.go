@@ -0,0 +1,268 @@
+package collections
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// interval is an immutable closed range [lo, hi], nodes swap whole intervals atomically
+// so that readers never observe a half updated bound.
+type interval struct {
+	lo, hi int
+}
+
+// intervalLink is the interval of a node and its next node. They are stored together: a merge
+// grows a node and unlinks its successor, a split links a new successor and shrinks the node,
+// a reader loading them one at a time could miss the values moved in either way.
+type intervalLink struct {
+	span interval
+	next *intervalNode
+}
+
+type intervalNode struct {
+	linkValue   atomic.Value
+	markedValue atomic.Value
+	// mergedValue is set before a node is marked when its interval moves into its predecessor
+	mergedValue atomic.Value
+	mutex       sync.Mutex
+}
+
+func (n *intervalNode) mark() {
+	n.markedValue.Store(true)
+}
+
+// retire marks n as dropped by a merge, its values are still present in its predecessor.
+func (n *intervalNode) retire() {
+	n.mergedValue.Store(true)
+	n.mark()
+}
+
+// removed reports whether n has been marked for a delete rather than retired by a merge. A
+// reader can reach a retired node after loading the old span of its predecessor, so the span
+// of a retired node is still trusted: its values were present when it was retired.
+func (n *intervalNode) removed() bool {
+	if !n.marked() {
+		return false
+	}
+	// loaded after the mark, retire sets it before
+	merged, _ := n.mergedValue.Load().(bool)
+	return !merged
+}
+
+func (n *intervalNode) marked() bool {
+	b, ok := n.markedValue.Load().(bool)
+	return b && ok
+}
+
+func (n *intervalNode) link() intervalLink {
+	l, _ := n.linkValue.Load().(intervalLink)
+	return l
+}
+
+func (n *intervalNode) next() *intervalNode {
+	return n.link().next
+}
+
+func (n *intervalNode) span() interval {
+	return n.link().span
+}
+
+// update replaces the interval and the next node at once, it must be called with n locked like
+// updateNext and updateSpan.
+func (n *intervalNode) update(lo, hi int, next *intervalNode) {
+	n.linkValue.Store(intervalLink{span: interval{lo: lo, hi: hi}, next: next})
+}
+
+func (n *intervalNode) updateNext(next *intervalNode) {
+	span := n.span()
+	n.update(span.lo, span.hi, next)
+}
+
+func (n *intervalNode) updateSpan(lo, hi int) {
+	n.update(lo, hi, n.next())
+}
+
+func newIntervalNode(lo, hi int) *intervalNode {
+	n := &intervalNode{}
+	n.update(lo, hi, nil)
+	return n
+}
+
+// testHookAfterLinkLoad is called by ConcurrentRangeSet.Contains after loading the link of a node.
+var testHookAfterLinkLoad func()
+
+var _ IntList = (*ConcurrentRangeSet)(nil)
+
+// ConcurrentRangeSet stores integers as sorted, non-overlapping and non-adjacent intervals,
+// so dense data costs one node per run instead of one node per value.
+// It uses the same lazy locking as ConcurrentIntList: the span and the next pointer of a node
+// are only changed while holding the node's lock.
+type ConcurrentRangeSet struct {
+	root *intervalNode
+	size int64
+}
+
+func NewConcurrentRangeSet() *ConcurrentRangeSet {
+	return &ConcurrentRangeSet{root: newIntervalNode(0, 0)}
+}
+
+// find returns the first node whose interval ends at or after value, and its predecessor.
+func (set *ConcurrentRangeSet) find(value int) (pre, current *intervalNode) {
+	pre = set.root
+	current = pre.next()
+	for current != nil && (current.marked() || current.span().hi < value) {
+		pre = current
+		current = pre.next()
+	}
+	return pre, current
+}
+
+// valid must be called with pre and current locked.
+func (set *ConcurrentRangeSet) valid(pre, current *intervalNode, value int) bool {
+	if pre.marked() || pre.next() != current {
+		return false
+	}
+	if pre != set.root && pre.span().hi >= value {
+		return false
+	}
+	return current == nil || (!current.marked() && current.span().hi >= value)
+}
+
+func (set *ConcurrentRangeSet) Contains(value int) bool {
+	for n := set.root.next(); n != nil; {
+		if n.removed() {
+			n = n.next()
+			continue
+		}
+		// load the span and the next node once, they may be replaced between two loads
+		l := n.link()
+		if testHookAfterLinkLoad != nil {
+			testHookAfterLinkLoad()
+		}
+		if l.span.hi >= value {
+			return l.span.lo <= value
+		}
+		n = l.next
+	}
+	return false
+}
+
+func (set *ConcurrentRangeSet) Insert(value int) bool {
+start:
+	pre, current := set.find(value)
+	if current != nil && current.span().lo <= value {
+		return false
+	}
+	// lock in the same reverse order as Delete, avoid dead lock
+	if current != nil {
+		current.mutex.Lock()
+	}
+	pre.mutex.Lock()
+	if !set.valid(pre, current, value) {
+		pre.mutex.Unlock()
+		if current != nil {
+			current.mutex.Unlock()
+		}
+		goto start
+	}
+	inserted := current == nil || current.span().lo > value
+	if inserted {
+		mergeLeft := pre != set.root && pre.span().hi == value-1
+		mergeRight := current != nil && current.span().lo == value+1
+		switch {
+		case mergeLeft && mergeRight:
+			// grow pre and unlink current at once. A reader may have loaded the old link of
+			// pre and go on to current, so current is retired, not removed, and keeps its span.
+			pre.update(pre.span().lo, current.span().hi, current.next())
+			current.retire()
+		case mergeLeft:
+			pre.updateSpan(pre.span().lo, value)
+		case mergeRight:
+			current.updateSpan(value, current.span().hi)
+		default:
+			newNode := newIntervalNode(value, value)
+			newNode.updateNext(current)
+			pre.updateNext(newNode)
+		}
+		atomic.AddInt64(&set.size, 1)
+	}
+	pre.mutex.Unlock()
+	if current != nil {
+		current.mutex.Unlock()
+	}
+	return inserted
+}
+
+func (set *ConcurrentRangeSet) Delete(value int) bool {
+start:
+	pre, current := set.find(value)
+	if current == nil || current.span().lo > value {
+		return false
+	}
+	current.mutex.Lock()
+	pre.mutex.Lock()
+	if !set.valid(pre, current, value) {
+		pre.mutex.Unlock()
+		current.mutex.Unlock()
+		goto start
+	}
+	span := current.span()
+	deleted := span.lo <= value
+	if deleted {
+		switch {
+		case span.lo == span.hi:
+			current.mark()
+			pre.updateNext(current.next())
+		case value == span.lo:
+			current.updateSpan(value+1, span.hi)
+		case value == span.hi:
+			current.updateSpan(span.lo, value-1)
+		default:
+			// split: link the upper half and shrink current at once, so values above
+			// value never disappear for a concurrent reader
+			upper := newIntervalNode(value+1, span.hi)
+			upper.updateNext(current.next())
+			current.update(span.lo, value-1, upper)
+		}
+		atomic.AddInt64(&set.size, -1)
+	}
+	pre.mutex.Unlock()
+	current.mutex.Unlock()
+	return deleted
+}
+
+// Range calls f for every value in ascending order. Like ConcurrentIntList.Range it ignores
+// modifications made during the walk, a value that is split off or merged concurrently may be
+// visited twice.
+func (set *ConcurrentRangeSet) Range(f func(value int) bool) {
+	set.RangeIntervals(func(lo, hi int) bool {
+		for v := lo; ; v++ {
+			if !f(v) {
+				return false
+			}
+			if v == hi {
+				return true
+			}
+		}
+	})
+}
+
+// RangeIntervals calls f for every stored interval [lo, hi] in ascending order.
+func (set *ConcurrentRangeSet) RangeIntervals(f func(lo, hi int) bool) {
+	for n := set.root.next(); n != nil; {
+		if n.removed() {
+			n = n.next()
+			continue
+		}
+		l := n.link()
+		if !f(l.span.lo, l.span.hi) {
+			return
+		}
+		n = l.next
+	}
+}
+
+// Len returns the number of values, not the number of intervals.
+func (set *ConcurrentRangeSet) Len() int {
+	return int(atomic.LoadInt64(&set.size))
+}
@@ -0,0 +1,203 @@
+package collections
+
+import (
+	"sync"
+	"testing"
+)
+
+func intervalsOf(set *ConcurrentRangeSet) [][2]int {
+	var spans [][2]int
+	set.RangeIntervals(func(lo, hi int) bool {
+		spans = append(spans, [2]int{lo, hi})
+		return true
+	})
+	return spans
+}
+
+func equalIntervals(a, b [][2]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRangeSet(t *testing.T) {
+	s := NewConcurrentRangeSet()
+	if s.Len() != 0 || s.Contains(0) || s.Delete(0) {
+		t.Fatal("invalid empty set")
+	}
+
+	// Merge on adjacent insert.
+	for _, v := range []int{1, 2, 3, 7, 8} {
+		if !s.Insert(v) {
+			t.Fatal("invalid insert")
+		}
+	}
+	if s.Insert(2) || s.Len() != 5 {
+		t.Fatal("invalid insert")
+	}
+	if got := intervalsOf(s); !equalIntervals(got, [][2]int{{1, 3}, {7, 8}}) {
+		t.Fatalf("invalid intervals %v", got)
+	}
+	s.Insert(5)
+	if got := intervalsOf(s); !equalIntervals(got, [][2]int{{1, 3}, {5, 5}, {7, 8}}) {
+		t.Fatalf("invalid intervals %v", got)
+	}
+	s.Insert(4)
+	s.Insert(6)
+	if got := intervalsOf(s); !equalIntervals(got, [][2]int{{1, 8}}) {
+		t.Fatalf("invalid intervals %v", got)
+	}
+	if s.Len() != 8 {
+		t.Fatal("invalid length")
+	}
+
+	// Split on mid-interval delete.
+	if !s.Delete(4) || s.Delete(4) || s.Contains(4) || s.Len() != 7 {
+		t.Fatal("invalid delete")
+	}
+	if got := intervalsOf(s); !equalIntervals(got, [][2]int{{1, 3}, {5, 8}}) {
+		t.Fatalf("invalid intervals %v", got)
+	}
+	if !s.Delete(1) || !s.Delete(8) {
+		t.Fatal("invalid delete")
+	}
+	if got := intervalsOf(s); !equalIntervals(got, [][2]int{{2, 3}, {5, 7}}) {
+		t.Fatalf("invalid intervals %v", got)
+	}
+
+	var values []int
+	s.Range(func(value int) bool {
+		values = append(values, value)
+		return true
+	})
+	expected := []int{2, 3, 5, 6, 7}
+	if len(values) != len(expected) {
+		t.Fatal("invalid range")
+	}
+	for i := range values {
+		if values[i] != expected[i] {
+			t.Fatal("invalid range")
+		}
+	}
+
+	// Concurrent insert of a dense range collapses into one interval.
+	s = NewConcurrentRangeSet()
+	const num = 10000
+	var wg sync.WaitGroup
+	for i := 0; i < num; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			s.Insert(i)
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+	if s.Len() != num {
+		t.Fatalf("invalid length expected %d, got %d", num, s.Len())
+	}
+	if got := intervalsOf(s); !equalIntervals(got, [][2]int{{0, num - 1}}) {
+		t.Fatalf("invalid intervals %v", got)
+	}
+
+	// Concurrent Insert and Delete in small zone.
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			for i := 0; i < 1000; i++ {
+				v := int(fastrandn(64))
+				if fastrandn(2) == 0 {
+					s.Delete(v)
+				} else {
+					s.Insert(v)
+				}
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+	var (
+		count  int
+		lastHi = -2
+	)
+	s.RangeIntervals(func(lo, hi int) bool {
+		if lo > hi || lo <= lastHi+1 {
+			t.Fatalf("invalid interval [%d, %d] after %d", lo, hi, lastHi)
+		}
+		count += hi - lo + 1
+		lastHi = hi
+		return true
+	})
+	if count != s.Len() {
+		t.Fatalf("invalid length expected %d, got %d", count, s.Len())
+	}
+}
+
+func TestRangeSetContainsDuringMerge(t *testing.T) {
+	s := NewConcurrentRangeSet()
+	for v := 0; v <= 20; v++ {
+		if v != 10 {
+			s.Insert(v)
+		}
+	}
+	// 15 is never deleted, a reader that loaded [0, 9] before 10 merged the intervals goes on
+	// to the retired [11, 20] and must still find it
+	retired := s.root.next().next()
+	testHookAfterLinkLoad = func() {
+		testHookAfterLinkLoad = nil
+		s.Insert(10)
+	}
+	if !s.Contains(15) || !equalIntervals(intervalsOf(s), [][2]int{{0, 20}}) {
+		t.Fatal("15 missing after a merge")
+	}
+	if !retired.marked() || retired.removed() || retired.span() != (interval{lo: 11, hi: 20}) {
+		t.Fatal("merged node not retired with its span")
+	}
+	// a reader that loaded [0, 20] before 10 split it goes on to the new [11, 20]
+	testHookAfterLinkLoad = func() {
+		testHookAfterLinkLoad = nil
+		s.Delete(10)
+	}
+	if !s.Contains(15) || !equalIntervals(intervalsOf(s), [][2]int{{0, 9}, {11, 20}}) {
+		t.Fatal("15 missing after a split")
+	}
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		for i := 0; i < 20000; i++ {
+			s.Insert(10)
+			s.Delete(10)
+		}
+		close(done)
+		wg.Done()
+	}()
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if !s.Contains(15) {
+					t.Error("15 missing during merges")
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if !equalIntervals(intervalsOf(s), [][2]int{{0, 9}, {11, 20}}) || s.Len() != 20 {
+		t.Fatalf("invalid intervals %v", intervalsOf(s))
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !collectionsdebug
+
+package collections
+
+// debugChecks is false in release builds, the assertions guarded by it are compiled out.
+const debugChecks = false
+
+func (intList *ConcurrentIntList) assertInserted(pre, n *intNode) {}
+
+func (intList *ConcurrentIntList) assertNoCycle(slow, n *intNode, i int) *intNode { return nil }
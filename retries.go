@@ -0,0 +1,30 @@
+package collections
+
+import "sync/atomic"
+
+// MaxRetriesObserved returns the largest number of times an Insert, Delete or ContainsStrict
+// had to restart its walk because a concurrent write invalidated it, since the list was built
+// or ResetMaxRetries was last called. A sudden rise signals pathological contention around a
+// few values. Operations that never retry pay nothing for the tracking, one that retried costs
+// an atomic load when it returns, and a compare-and-swap when it raises the maximum.
+func (intList *ConcurrentIntList) MaxRetriesObserved() int {
+	return int(atomic.LoadInt64(&intList.maxRetries))
+}
+
+// ResetMaxRetries sets MaxRetriesObserved back to 0, e.g. after each alerting interval.
+func (intList *ConcurrentIntList) ResetMaxRetries() {
+	atomic.StoreInt64(&intList.maxRetries, 0)
+}
+
+// observeRetries raises maxRetries to retries if it is lower.
+func (intList *ConcurrentIntList) observeRetries(retries int) {
+	if retries <= 0 {
+		return
+	}
+	for {
+		seen := atomic.LoadInt64(&intList.maxRetries)
+		if int64(retries) <= seen || atomic.CompareAndSwapInt64(&intList.maxRetries, seen, int64(retries)) {
+			return
+		}
+	}
+}
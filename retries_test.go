@@ -0,0 +1,56 @@
+package collections
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMaxRetriesObserved(t *testing.T) {
+	l := newListOf(1, 2)
+	l.Insert(3)
+	l.Delete(3)
+	if l.MaxRetriesObserved() != 0 {
+		t.Fatalf("retries observed without contention %d", l.MaxRetriesObserved())
+	}
+
+	// Insert(3) walks up to 2 while a Delete of 2 holds it, it has to retry once 2 is unlinked.
+	marked, resume := make(chan struct{}), make(chan struct{})
+	testHookBeforeUnlink = func() {
+		marked <- struct{}{}
+		<-resume
+	}
+	defer func() { testHookBeforeUnlink = nil }()
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		l.Delete(2)
+		wg.Done()
+	}()
+	<-marked
+	go func() {
+		l.Insert(3)
+		wg.Done()
+	}()
+	// let the insert reach the lock of 2
+	time.Sleep(20 * time.Millisecond)
+	close(resume)
+	wg.Wait()
+	testHookBeforeUnlink = nil
+	if l.MaxRetriesObserved() < 1 {
+		t.Fatalf("invalid max retries %d", l.MaxRetriesObserved())
+	}
+	if l.Contains(2) || !l.Contains(3) || l.HealthCheck() != nil {
+		t.Fatal("invalid list after contended writes")
+	}
+
+	l.ResetMaxRetries()
+	if l.MaxRetriesObserved() != 0 {
+		t.Fatalf("invalid max retries after reset %d", l.MaxRetriesObserved())
+	}
+	l.observeRetries(3)
+	l.observeRetries(2)
+	if l.MaxRetriesObserved() != 3 {
+		t.Fatalf("invalid max retries expected %d, got %d", 3, l.MaxRetriesObserved())
+	}
+}
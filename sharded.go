@@ -0,0 +1,198 @@
+package collections
+
+import (
+	"container/heap"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+)
+
+// defaultShards is the shard count used when a caller doesn't choose one.
+const defaultShards = 16
+
+// shardTarget is the number of values per shard aimed at when the shard count is derived from
+// WithExpectedSize, up to maxShards.
+const (
+	shardTarget = 256
+	maxShards   = 1024
+)
+
+var _ IntList = (*ShardedIntList)(nil)
+
+// ShardedIntList spreads values over independent ConcurrentIntList shards by hash, so each walk
+// only covers the shard owning the value. Range and ToSlice merge the shards back into
+// ascending order from per-shard snapshots.
+type ShardedIntList struct {
+	shards []*ConcurrentIntList
+	// seed is mixed into the shard hash
+	seed int64
+	// canonicalizer of WithCanonicalizer, values with the same canonical form must share a shard
+	canonicalizer func(value int) int
+}
+
+// NewShardedIntList returns a list with the given number of shards, each built with opts.
+// shards lower than 1 means one shard per shardTarget values of WithExpectedSize if it is
+// given, defaultShards otherwise. Each shard is told to expect its share of the values, and
+// WithComparator is ignored since the shards are merged in ascending order. WithCapacity bounds
+// the whole list rather than each shard, the shards count their values against one shared
+// size. Values are spread over the shards by a hash seeded with WithSeed, or a random seed, of
+// their canonical form if WithCanonicalizer is given.
+func NewShardedIntList(shards int, opts ...Option) *ShardedIntList {
+	s, _ := newShardedIntList(shards, opts)
+	return s
+}
+
+// NewShardedIntListE is NewShardedIntList refusing a shard count below 1 instead of using the
+// default, and reporting invalid options like NewConcurrentIntListE.
+func NewShardedIntListE(shards int, opts ...Option) (*ShardedIntList, error) {
+	if shards < 1 {
+		return nil, fmt.Errorf("%w: %d shards", ErrInvalidOption, shards)
+	}
+	s, err := newShardedIntList(shards, opts)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func newShardedIntList(shards int, opts []Option) (*ShardedIntList, error) {
+	// options only set fields, apply them to a scratch list to read the hint
+	var probe ConcurrentIntList
+	for _, opt := range opts {
+		opt(&probe)
+	}
+	expected := max(probe.expectedSize, 0)
+	if shards < 1 {
+		shards = defaultShards
+		if expected > 0 {
+			shards = min(max(expected/shardTarget, 1), maxShards)
+		}
+	}
+	shardOpts := append(slices.Clip(opts), WithComparator(IntLess))
+	if expected > 0 {
+		shardOpts = append(shardOpts, WithExpectedSize(expected/shards))
+	}
+	seed := probe.seed
+	if !probe.hasSeed {
+		seed = rand.Int64()
+	}
+	s := &ShardedIntList{shards: make([]*ConcurrentIntList, shards), seed: seed, canonicalizer: probe.canonicalizer}
+	var pool *int64
+	if probe.capacity > 0 {
+		pool = new(int64)
+	}
+	var err error
+	for i := range s.shards {
+		s.shards[i], err = newConcurrentIntList(IntLess, shardOpts)
+		s.shards[i].pool = pool
+	}
+	return s, err
+}
+
+func (s *ShardedIntList) shardIndex(value int) int {
+	// the shard canonicalizes value itself, the canonical form only picks the shard here
+	if s.canonicalizer != nil {
+		value = s.canonicalizer(value)
+	}
+	// fibonacci hashing, spreads consecutive values over different shards
+	h := (uint64(value) ^ uint64(s.seed)) * 0x9E3779B97F4A7C15
+	return int((h >> 32) % uint64(len(s.shards)))
+}
+
+// Seed returns the seed of the shard hash, pass it to WithSeed to rebuild the same layout.
+func (s *ShardedIntList) Seed() int64 {
+	return s.seed
+}
+
+func (s *ShardedIntList) shard(value int) *ConcurrentIntList {
+	return s.shards[s.shardIndex(value)]
+}
+
+// insertSorted loads ascending values, each shard is filled in a single pass.
+func (s *ShardedIntList) insertSorted(values []int) {
+	parts := make([][]int, len(s.shards))
+	for _, value := range values {
+		i := s.shardIndex(value)
+		parts[i] = append(parts[i], value)
+	}
+	for i, part := range parts {
+		s.shards[i].InsertSorted(part)
+	}
+}
+
+func (s *ShardedIntList) Contains(value int) bool {
+	return s.shard(value).Contains(value)
+}
+
+func (s *ShardedIntList) Insert(value int) bool {
+	return s.shard(value).Insert(value)
+}
+
+func (s *ShardedIntList) Delete(value int) bool {
+	return s.shard(value).Delete(value)
+}
+
+// Range calls f in ascending order over a merge of per-shard snapshots, so unlike
+// ConcurrentIntList.Range it never observes modifications made after it started.
+func (s *ShardedIntList) Range(f func(value int) bool) {
+	for _, value := range s.ToSlice() {
+		if !f(value) {
+			return
+		}
+	}
+}
+
+// ToSlice returns the values of all shards in ascending order, the shard snapshots are merged
+// at once through a heap, in O(n log shards).
+func (s *ShardedIntList) ToSlice() []int {
+	var total int
+	runs := make(sortedRuns, 0, len(s.shards))
+	for _, shard := range s.shards {
+		if values := shard.ToSlice(); len(values) > 0 {
+			runs = append(runs, values)
+			total += len(values)
+		}
+	}
+	heap.Init(&runs)
+	merged := make([]int, 0, total)
+	for runs.Len() > 0 {
+		merged = append(merged, runs.next())
+	}
+	return merged
+}
+
+func (s *ShardedIntList) Len() int {
+	var n int
+	for _, shard := range s.shards {
+		n += shard.Len()
+	}
+	return n
+}
+
+// ShardStats returns the Len of every shard, a skewed distribution means the keys defeat the
+// hash and most walks cover one big shard.
+func (s *ShardedIntList) ShardStats() []int {
+	stats := make([]int, len(s.shards))
+	for i, shard := range s.shards {
+		stats[i] = shard.Len()
+	}
+	return stats
+}
+
+// ShardOf returns the index, in ShardStats, of the shard owning value.
+func (s *ShardedIntList) ShardOf(value int) int {
+	return s.shardIndex(value)
+}
+
+// NumShards returns the number of shards, the valid indexes of RangeShard are [0, NumShards).
+func (s *ShardedIntList) NumShards() int {
+	return len(s.shards)
+}
+
+// RangeShard calls f in ascending order over the values of one shard, like
+// ConcurrentIntList.Range, so consumers can each walk a shard in parallel without merging.
+// The order across shards is not maintained, a shard holds values spread over the whole
+// range. It panics if shard is not in [0, NumShards).
+func (s *ShardedIntList) RangeShard(shard int, f func(value int) bool) {
+	s.shards[shard].Range(f)
+}
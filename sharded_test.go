@@ -0,0 +1,225 @@
+package collections
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestShardedIntList(t *testing.T) {
+	s := NewShardedIntList(4)
+	if s.Len() != 0 || s.Contains(1) || s.Delete(1) {
+		t.Fatal("invalid empty list")
+	}
+
+	const num = 1000
+	var wg sync.WaitGroup
+	for i := 0; i < num; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			if !s.Insert(i) {
+				panic("invalid insert")
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+	if s.Len() != num || s.Insert(10) {
+		t.Fatal("invalid insert")
+	}
+	for _, shard := range s.shards {
+		if shard.Len() == 0 {
+			t.Fatal("values aren't spread over shards")
+		}
+	}
+
+	var expected int
+	s.Range(func(value int) bool {
+		if value != expected {
+			t.Fatalf("invalid range expected %d, got %d", expected, value)
+		}
+		expected++
+		return true
+	})
+	if expected != num {
+		t.Fatal("invalid range")
+	}
+
+	for i := 0; i < num; i += 2 {
+		if !s.Delete(i) {
+			t.Fatal("invalid delete")
+		}
+	}
+	if s.Len() != num/2 || s.Contains(0) || !s.Contains(1) {
+		t.Fatal("invalid delete")
+	}
+	values := s.ToSlice()
+	for i, v := range values {
+		if v != 2*i+1 {
+			t.Fatalf("invalid value %d at %d", v, i)
+		}
+	}
+}
+
+func TestShardedExpectedSize(t *testing.T) {
+	if s := NewShardedIntList(0); len(s.shards) != defaultShards {
+		t.Fatalf("invalid shard count expected %d, got %d", defaultShards, len(s.shards))
+	}
+	if s := NewShardedIntList(0, WithExpectedSize(100*shardTarget)); len(s.shards) != 100 {
+		t.Fatalf("invalid shard count expected %d, got %d", 100, len(s.shards))
+	}
+	if s := NewShardedIntList(0, WithExpectedSize(10)); len(s.shards) != 1 {
+		t.Fatalf("invalid shard count expected %d, got %d", 1, len(s.shards))
+	}
+	if s := NewShardedIntList(0, WithExpectedSize(1<<30)); len(s.shards) != maxShards {
+		t.Fatalf("invalid shard count expected %d, got %d", maxShards, len(s.shards))
+	}
+	// an explicit count wins over the hint, each shard expects its share
+	s := NewShardedIntList(4, WithExpectedSize(4000), WithBloomFilter(10, 0.01), WithComparator(intGreater))
+	if len(s.shards) != 4 || s.shards[0].expectedSize != 1000 || s.shards[0].bloomN != 1000 {
+		t.Fatal("invalid sharded list sized by hint")
+	}
+	for i := 0; i < 100; i++ {
+		s.Insert(i)
+	}
+	if got := s.ToSlice(); len(got) != 100 || got[0] != 0 {
+		t.Fatal("invalid order of sharded list with a comparator")
+	}
+
+	// many shards, most of them empty, merge back into ascending order
+	many := NewShardedIntList(maxShards)
+	values := make([]int, 3000)
+	for i := range values {
+		values[i] = len(values) - 2*i
+		many.Insert(values[i])
+	}
+	slices.Sort(values)
+	if got := many.ToSlice(); !slices.Equal(got, values) {
+		t.Fatal("invalid merge of many shards")
+	}
+	if got := NewShardedIntList(maxShards).ToSlice(); len(got) != 0 {
+		t.Fatalf("invalid merge of empty shards %v", got)
+	}
+
+	if _, err := NewShardedIntListE(4, WithExpectedSize(-1)); !errors.Is(err, ErrInvalidOption) {
+		t.Fatalf("invalid negative expected size accepted: %v", err)
+	}
+}
+
+func TestShardedSeed(t *testing.T) {
+	a := NewShardedIntList(8, WithSeed(42))
+	b := NewShardedIntList(8, WithSeed(42))
+	if a.Seed() != 42 {
+		t.Fatalf("invalid seed expected %d, got %d", 42, a.Seed())
+	}
+	for i := 0; i < 1000; i++ {
+		value := int(fastrandn(10000))
+		a.Insert(value)
+		b.Insert(value)
+		if i%3 == 0 {
+			a.Delete(value / 2)
+			b.Delete(value / 2)
+		}
+	}
+	for i := range a.shards {
+		if !slices.Equal(a.shards[i].ToSlice(), b.shards[i].ToSlice()) {
+			t.Fatalf("invalid shard %d with the same seed (seed %d)", i, a.Seed())
+		}
+	}
+
+	// A different seed gives another layout of the same values.
+	c := NewShardedIntList(8, WithSeed(43))
+	for _, value := range a.ToSlice() {
+		c.Insert(value)
+	}
+	var same int
+	for i := range a.shards {
+		if slices.Equal(a.shards[i].ToSlice(), c.shards[i].ToSlice()) {
+			same++
+		}
+	}
+	if same == len(a.shards) || !slices.Equal(a.ToSlice(), c.ToSlice()) {
+		t.Fatalf("invalid layout with another seed (seed %d)", c.Seed())
+	}
+}
+
+func TestShardStats(t *testing.T) {
+	s := NewShardedIntList(8)
+	for i := 0; i < 8000; i++ {
+		s.Insert(i)
+	}
+	stats := s.ShardStats()
+	if len(stats) != 8 {
+		t.Fatalf("invalid stats length expected %d, got %d", 8, len(stats))
+	}
+	var sum int
+	for i, n := range stats {
+		// consecutive keys are spread evenly
+		if n < 500 || n > 1500 {
+			t.Fatalf("invalid stats of shard %d: %v (seed %d)", i, stats, s.Seed())
+		}
+		sum += n
+	}
+	if sum != s.Len() {
+		t.Fatalf("invalid stats sum expected %d, got %d", s.Len(), sum)
+	}
+
+	// Keys that all hash to one shard.
+	skewed := NewShardedIntList(8, WithSeed(s.Seed()))
+	target := s.ShardOf(0)
+	for i := 0; i < 8000; i++ {
+		if s.ShardOf(i) == target {
+			skewed.Insert(i)
+		}
+		if skewed.ShardOf(i) != s.ShardOf(i) {
+			t.Fatalf("invalid shard of %d with the same seed", i)
+		}
+	}
+	stats = skewed.ShardStats()
+	for i, n := range stats {
+		if (i == target) != (n > 0) || n != skewed.shards[i].Len() {
+			t.Fatalf("invalid stats of skewed keys %v", stats)
+		}
+	}
+}
+
+func TestRangeShard(t *testing.T) {
+	s := NewShardedIntList(4)
+	for i := 999; i >= 0; i-- {
+		s.Insert(i)
+	}
+	if s.NumShards() != 4 {
+		t.Fatalf("invalid shard count expected %d, got %d", 4, s.NumShards())
+	}
+	parts := make([][]int, s.NumShards())
+	var wg sync.WaitGroup
+	for i := range s.NumShards() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.RangeShard(i, func(value int) bool {
+				parts[i] = append(parts[i], value)
+				return true
+			})
+		}()
+	}
+	wg.Wait()
+	var union []int
+	for i, part := range parts {
+		if !slices.IsSorted(part) {
+			t.Fatalf("invalid order of shard %d", i)
+		}
+		for _, value := range part {
+			if s.ShardOf(value) != i {
+				t.Fatalf("invalid shard of %d expected %d, got %d", value, s.ShardOf(value), i)
+			}
+		}
+		union = append(union, part...)
+	}
+	slices.Sort(union)
+	if !slices.Equal(union, s.ToSlice()) {
+		t.Fatalf("invalid union of shards, %d values", len(union))
+	}
+}
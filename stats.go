@@ -0,0 +1,151 @@
+package collections
+
+import (
+	"iter"
+	"math"
+	"slices"
+)
+
+// distance returns |a - b|, consecutive values of a descending list give the same gaps as
+// the ascending one. The difference always fits in an uint64, a distance beyond math.MaxInt
+// saturates at math.MaxInt.
+func distance(a, b int) int {
+	lo, hi := min(a, b), max(a, b)
+	if d := uint64(hi) - uint64(lo); d <= math.MaxInt {
+		return int(d)
+	}
+	return math.MaxInt
+}
+
+// rangePairs calls f for every two consecutive values in the list's order.
+func (intList *ConcurrentIntList) rangePairs(f func(pre, value int) bool) {
+	var (
+		pre    int
+		hasPre bool
+	)
+	intList.Range(func(value int) bool {
+		if hasPre && !f(pre, value) {
+			return false
+		}
+		pre, hasPre = value, true
+		return true
+	})
+}
+
+// MaxGap returns the largest distance between two consecutive values, ok is false if the
+// list has fewer than two values. A distance beyond math.MaxInt is reported as math.MaxInt.
+func (intList *ConcurrentIntList) MaxGap() (gap int, ok bool) {
+	intList.rangePairs(func(pre, value int) bool {
+		if d := distance(pre, value); !ok || d > gap {
+			gap, ok = d, true
+		}
+		return true
+	})
+	return gap, ok
+}
+
+// Gaps returns the distances between every two consecutive values in the list's order, a
+// distance beyond math.MaxInt, e.g. from -1 to math.MaxInt, is reported as math.MaxInt.
+func (intList *ConcurrentIntList) Gaps() []int {
+	var gaps []int
+	intList.rangePairs(func(pre, value int) bool {
+		gaps = append(gaps, distance(pre, value))
+		return true
+	})
+	return gaps
+}
+
+// ConsecutiveRuns returns the maximal runs of consecutive integers in the list as inclusive
+// [start, end] ranges, e.g. [[1 3] [5 5]] for 1, 2, 3 and 5, in a single walk. The runs follow
+// the list's order, for a descending list they come from the largest down, but start is always
+// the lowest value of a run.
+func (intList *ConcurrentIntList) ConsecutiveRuns() [][2]int {
+	var runs [][2]int
+	intList.Range(func(value int) bool {
+		if n := len(runs); n > 0 {
+			run := &runs[n-1]
+			switch {
+			case run[1] != math.MaxInt && value == run[1]+1:
+				run[1] = value
+				return true
+			case run[0] != math.MinInt && value == run[0]-1:
+				run[0] = value
+				return true
+			}
+		}
+		runs = append(runs, [2]int{value, value})
+		return true
+	})
+	return runs
+}
+
+// MissingInRangeSeq returns an iterator over the integers in [lo, hi], ascending, that are not
+// in the list. Only the present values of the range are buffered, the missing ones are
+// generated lazily, so it is safe on huge ranges.
+func (intList *ConcurrentIntList) MissingInRangeSeq(lo, hi int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		if lo > hi {
+			return
+		}
+		var present []int
+		intList.Range(func(value int) bool {
+			if value >= lo && value <= hi {
+				present = append(present, value)
+			}
+			return true
+		})
+		// a descending or custom ordered list doesn't walk in ascending order
+		slices.Sort(present)
+		// yieldSpan emits [from, to] without overflowing at math.MaxInt
+		yieldSpan := func(from, to int) bool {
+			for v := from; ; v++ {
+				if !yield(v) {
+					return false
+				}
+				if v == to {
+					return true
+				}
+			}
+		}
+		next := lo
+		for _, value := range present {
+			if value > next && !yieldSpan(next, value-1) {
+				return
+			}
+			if value == hi {
+				return
+			}
+			next = value + 1
+		}
+		yieldSpan(next, hi)
+	}
+}
+
+// MissingInRange returns the integers in [lo, hi], ascending, that are not in the list.
+// The result holds up to hi-lo+1 values, use MissingInRangeSeq for huge ranges.
+func (intList *ConcurrentIntList) MissingInRange(lo, hi int) []int {
+	return slices.Collect(intList.MissingInRangeSeq(lo, hi))
+}
+
+// Sum returns the sum of all values, it may overflow like any int addition.
+func (intList *ConcurrentIntList) Sum() int {
+	var sum int
+	intList.Range(func(value int) bool {
+		sum += value
+		return true
+	})
+	return sum
+}
+
+// CountIf returns how many values satisfy pred in a single walk. Like Range it is best effort
+// under concurrent writes, values inserted or deleted during the walk may or may not be counted.
+func (intList *ConcurrentIntList) CountIf(pred func(value int) bool) int {
+	var count int
+	intList.Range(func(value int) bool {
+		if pred(value) {
+			count++
+		}
+		return true
+	})
+	return count
+}
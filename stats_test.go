@@ -0,0 +1,155 @@
+package collections
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestGaps(t *testing.T) {
+	l := NewConcurrentIntList()
+	if _, ok := l.MaxGap(); ok || len(l.Gaps()) != 0 {
+		t.Fatal("invalid gaps of empty list")
+	}
+	l.Insert(5)
+	if _, ok := l.MaxGap(); ok || len(l.Gaps()) != 0 {
+		t.Fatal("invalid gaps of single element list")
+	}
+
+	// Two elements.
+	l.Insert(-3)
+	if gap, ok := l.MaxGap(); !ok || gap != 8 {
+		t.Fatalf("invalid max gap expected %d, got %d", 8, gap)
+	}
+	if gaps := l.Gaps(); len(gaps) != 1 || gaps[0] != 8 {
+		t.Fatalf("invalid gaps %v", gaps)
+	}
+
+	// Uniform spacing.
+	u := NewDescendingIntList()
+	for i := 0; i < 10; i++ {
+		u.Insert(i * 4)
+	}
+	gaps := u.Gaps()
+	if len(gaps) != 9 {
+		t.Fatalf("invalid gaps %v", gaps)
+	}
+	for _, g := range gaps {
+		if g != 4 {
+			t.Fatalf("invalid gaps %v", gaps)
+		}
+	}
+	if gap, ok := u.MaxGap(); !ok || gap != 4 {
+		t.Fatalf("invalid max gap expected %d, got %d", 4, gap)
+	}
+
+	u.Insert(100)
+	if gap, _ := u.MaxGap(); gap != 64 {
+		t.Fatalf("invalid max gap expected %d, got %d", 64, gap)
+	}
+
+	// Distances beyond math.MaxInt saturate instead of overflowing.
+	e := newListOf(math.MinInt, -1, math.MaxInt)
+	if gap, ok := e.MaxGap(); !ok || gap != math.MaxInt {
+		t.Fatalf("invalid max gap of extreme values %d", gap)
+	}
+	if gaps := e.Gaps(); !slices.Equal(gaps, []int{math.MaxInt, math.MaxInt}) {
+		t.Fatalf("invalid gaps of extreme values %v", gaps)
+	}
+}
+
+func TestConsecutiveRuns(t *testing.T) {
+	if runs := NewConcurrentIntList().ConsecutiveRuns(); len(runs) != 0 {
+		t.Fatalf("invalid runs of an empty list %v", runs)
+	}
+	for _, c := range []struct {
+		values []int
+		runs   [][2]int
+	}{
+		{[]int{4, 5, 6, 7}, [][2]int{{4, 7}}},
+		{[]int{1, 3, 5}, [][2]int{{1, 1}, {3, 3}, {5, 5}}},
+		{[]int{1, 2, 3, 5}, [][2]int{{1, 3}, {5, 5}}},
+		{[]int{-2, -1, 0, 1, 9, 11, 12}, [][2]int{{-2, 1}, {9, 9}, {11, 12}}},
+		{[]int{math.MinInt, math.MinInt + 1, math.MaxInt - 1, math.MaxInt}, [][2]int{{math.MinInt, math.MinInt + 1}, {math.MaxInt - 1, math.MaxInt}}},
+	} {
+		if runs := newListOf(c.values...).ConsecutiveRuns(); !slices.Equal(runs, c.runs) {
+			t.Fatalf("invalid runs of %v expected %v, got %v", c.values, c.runs, runs)
+		}
+	}
+
+	d := NewDescendingIntList()
+	d.InsertSorted([]int{5, 3, 2, 1})
+	if runs := d.ConsecutiveRuns(); !slices.Equal(runs, [][2]int{{5, 5}, {1, 3}}) {
+		t.Fatalf("invalid runs of a descending list %v", runs)
+	}
+}
+
+func TestMissingInRange(t *testing.T) {
+	l := NewConcurrentIntList()
+	for i := 10; i <= 20; i++ {
+		l.Insert(i)
+	}
+
+	// Fully dense range.
+	if missing := l.MissingInRange(10, 20); len(missing) != 0 {
+		t.Fatalf("invalid missing %v", missing)
+	}
+	if missing := l.MissingInRange(12, 15); len(missing) != 0 {
+		t.Fatalf("invalid missing %v", missing)
+	}
+
+	// Sparse range.
+	l.Delete(13)
+	l.Delete(14)
+	l.Delete(20)
+	expected := []int{7, 8, 9, 13, 14, 20, 21}
+	missing := l.MissingInRange(7, 21)
+	if !slices.Equal(missing, expected) {
+		t.Fatalf("invalid missing expected %v, got %v", expected, missing)
+	}
+
+	// Descending list gives the same ascending result.
+	d := NewDescendingIntList()
+	d.InsertSeq(l.All())
+	if missing := d.MissingInRange(7, 21); !slices.Equal(missing, expected) {
+		t.Fatalf("invalid missing expected %v, got %v", expected, missing)
+	}
+
+	if len(l.MissingInRange(5, 4)) != 0 {
+		t.Fatal("invalid missing of empty range")
+	}
+	if missing := NewConcurrentIntList().MissingInRange(math.MaxInt-1, math.MaxInt); len(missing) != 2 {
+		t.Fatalf("invalid missing at max int %v", missing)
+	}
+
+	// The iterator stops early on a huge range.
+	var count int
+	for range l.MissingInRangeSeq(math.MinInt, math.MaxInt) {
+		count++
+		if count == 100 {
+			break
+		}
+	}
+	if count != 100 {
+		t.Fatal("invalid missing iterator")
+	}
+}
+
+func TestCountIf(t *testing.T) {
+	l := NewConcurrentIntList()
+	for i := 1; i <= 10; i++ {
+		l.Insert(i)
+	}
+	if n := l.CountIf(func(value int) bool { return value%2 == 0 }); n != 5 {
+		t.Fatalf("invalid count of evens expected %d, got %d", 5, n)
+	}
+	if n := l.CountIf(func(int) bool { return true }); n != 10 {
+		t.Fatalf("invalid count of all expected %d, got %d", 10, n)
+	}
+	if n := l.CountIf(func(value int) bool { return value > 10 }); n != 0 {
+		t.Fatalf("invalid count of none expected %d, got %d", 0, n)
+	}
+	if n := NewConcurrentIntList().CountIf(func(int) bool { return true }); n != 0 {
+		t.Fatalf("invalid count on empty list expected %d, got %d", 0, n)
+	}
+}
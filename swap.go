@@ -0,0 +1,140 @@
+package collections
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// exclusiveWriteBoth runs f as an exclusive write on both a and b. The lists are always
+// locked in address order, so concurrent calls on the same pair can't dead lock.
+func exclusiveWriteBoth(a, b *ConcurrentIntList, f func()) error {
+	if uintptr(unsafe.Pointer(a)) > uintptr(unsafe.Pointer(b)) {
+		a, b = b, a
+	}
+	var err error
+	if outer := a.exclusiveWrite(func() {
+		err = b.exclusiveWrite(f)
+	}); outer != nil {
+		return outer
+	}
+	return err
+}
+
+// ErrIncompatible is returned by Swap when a list's values don't follow the other list's order.
+var ErrIncompatible = errors.New("collections: lists have different orders")
+
+// Swap atomically exchanges the contents of a and b. Writes to both lists are held back while
+// the head pointers and sizes are exchanged, so no write is lost or applied to the wrong list,
+// and a Range starting after Swap returns sees the new contents. A Range in progress is not
+// held back: it keeps walking the chain it started on, which now belongs to the other list, and
+// may see writes made to that list after the swap. Swap is a write on both lists: it waits while
+// either is frozen, and returns ErrClosed or ErrFrozen, doing nothing, if either refuses writes.
+//
+// Each list must be able to hold the other's values: Swap returns an error wrapping ErrFull if
+// they exceed its capacity, ErrOutOfDomain if one is out of its domain, ErrIncompatible if they
+// don't follow its order. The check is constant time when both lists have the same built-in
+// order and the domain of each covers the other's, otherwise it walks the values.
+func Swap(a, b *ConcurrentIntList) error {
+	if a == b {
+		return nil
+	}
+	var err error
+	if werr := exclusiveWriteBoth(a, b, func() {
+		if err = a.accepts(b); err == nil {
+			err = b.accepts(a)
+		}
+		if err != nil {
+			return
+		}
+		a.relink(func() {
+			b.relink(func() {
+				aHead, bHead := a.root.next(), b.root.next()
+				a.root.updateNext(bHead)
+				b.root.updateNext(aHead)
+			})
+		})
+		aSize, bSize := atomic.LoadInt64(&a.size), atomic.LoadInt64(&b.size)
+		atomic.StoreInt64(&a.size, bSize)
+		atomic.StoreInt64(&b.size, aSize)
+		a.versionIncr()
+		b.versionIncr()
+		a.rebuildBloomFilter()
+		b.rebuildBloomFilter()
+	}); werr != nil {
+		return werr
+	}
+	return err
+}
+
+// accepts returns why the nodes of other can't be moved to the list as they are, or nil. It
+// must be called during an exclusive write on both lists.
+func (intList *ConcurrentIntList) accepts(other *ConcurrentIntList) error {
+	if size := atomic.LoadInt64(&other.size); intList.capacity > 0 && size > intList.capacity {
+		return fmt.Errorf("%w: %d values exceed the capacity %d", ErrFull, size, intList.capacity)
+	}
+	inDomain := !intList.hasDomain || (other.hasDomain &&
+		other.domainMin >= intList.domainMin && other.domainMax <= intList.domainMax)
+	sameOrder := builtinOrder(intList.less) != 0 && builtinOrder(intList.less) == builtinOrder(other.less)
+	if inDomain && sameOrder {
+		return nil
+	}
+	var (
+		pre    int
+		hasPre bool
+	)
+	for n := nextLive(other.root.next()); n != nil; n = nextLive(n.next()) {
+		if !inDomain && !intList.inDomain(n.value) {
+			return fmt.Errorf("%w: %d", ErrOutOfDomain, n.value)
+		}
+		if !sameOrder && hasPre && !intList.less(pre, n.value) {
+			return fmt.Errorf("%w: %d is not ordered after %d", ErrIncompatible, n.value, pre)
+		}
+		pre, hasPre = n.value, true
+	}
+	return nil
+}
+
+// builtinOrder identifies less if it is IntLess or intGreater, it returns 0 for any other
+// comparator since two closures can share their code but not their behavior.
+func builtinOrder(less func(a, b int) bool) uintptr {
+	p := reflect.ValueOf(less).Pointer()
+	if p == reflect.ValueOf(IntLess).Pointer() || p == reflect.ValueOf(intGreater).Pointer() {
+		return p
+	}
+	return 0
+}
+
+// Transfer atomically moves value from one list to the other, it returns false, changing
+// nothing, if value is not in from or to doesn't take it: already present, out of its domain or
+// beyond its capacity. value is looked up in from by its canonical form for from, and inserted
+// into to as its canonical form for to. Like Swap it is a write on both lists, which are locked in address order
+// so that concurrent transfers in opposite directions can't dead lock, and it returns false if
+// either refuses writes. An observer can't see value in neither list: it is inserted into to
+// before it is deleted from from, so for a moment both hold it.
+func Transfer(from, to *ConcurrentIntList, value int) bool {
+	if from == to {
+		return false
+	}
+	value = from.canon(value)
+	stored := to.canon(value)
+	var moved bool
+	exclusiveWriteBoth(from, to, func() {
+		// no write is in flight, a present value can't be deleted meanwhile
+		if !from.Contains(value) || !to.inDomain(stored) {
+			return
+		}
+		if _, inserted, err := to.insertAfter(to.root, stored, time.Time{}, nil); err != nil || !inserted {
+			return
+		}
+		_, _, moved, _ = from.removeAfter(from.root, value, true, time.Time{})
+	})
+	if moved {
+		// outside of the write, rebuilding waits for in-flight writes
+		from.bloomDeleted()
+	}
+	return moved
+}
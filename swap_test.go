@@ -0,0 +1,190 @@
+package collections
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSwap(t *testing.T) {
+	a, b := NewConcurrentIntList(), NewConcurrentIntList()
+	for i := 0; i < 100; i++ {
+		a.Insert(i)
+		b.Insert(100 + i)
+	}
+	b.Insert(1000)
+
+	Swap(a, b)
+	if a.Len() != 101 || b.Len() != 100 || !a.Contains(1000) || b.Contains(1000) || !b.Contains(0) {
+		t.Fatal("invalid swap")
+	}
+	Swap(b, a)
+	Swap(a, a)
+	if a.Len() != 100 || !a.Contains(0) || !b.Contains(1000) {
+		t.Fatal("invalid swap back")
+	}
+	b.Delete(1000)
+
+	// Readers always see one whole set.
+	var (
+		wg   sync.WaitGroup
+		stop int32
+	)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			for atomic.LoadInt32(&stop) == 0 {
+				var low, high int
+				a.Range(func(value int) bool {
+					if value < 100 {
+						low++
+					} else {
+						high++
+					}
+					return true
+				})
+				if !(low == 100 && high == 0) && !(low == 0 && high == 100) {
+					panic("range sees a mix of both lists")
+				}
+			}
+			wg.Done()
+		}()
+	}
+	for i := 0; i < 1000; i++ {
+		if i%2 == 0 {
+			Swap(a, b)
+		} else {
+			Swap(b, a)
+		}
+	}
+	atomic.StoreInt32(&stop, 1)
+	wg.Wait()
+	if a.Len() != 100 || b.Len() != 100 || !a.IsSorted() || !b.IsSorted() {
+		t.Fatal("invalid lists after swaps")
+	}
+
+	// Swap is a write, it waits for a frozen list and is a no-op on a closed one.
+	a.Freeze()
+	done := make(chan struct{})
+	go func() {
+		Swap(a, b)
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("swap doesn't wait for a frozen list")
+	case <-time.After(20 * time.Millisecond):
+	}
+	a.Unfreeze()
+	<-done
+	low := a.Contains(0)
+	b.Close()
+	if err := Swap(a, b); !errors.Is(err, ErrClosed) || a.Contains(0) != low {
+		t.Fatalf("invalid swap with a closed list %v", err)
+	}
+}
+
+func TestSwapIncompatible(t *testing.T) {
+	values := make([]int, 100)
+	for i := range values {
+		values[i] = i
+	}
+	big := newListOf(values...)
+
+	small := NewConcurrentIntList(WithCapacity(10))
+	small.Insert(1)
+	if err := Swap(small, big); !errors.Is(err, ErrFull) || small.Len() != 1 || big.Len() != 100 {
+		t.Fatalf("invalid swap beyond the capacity %v", err)
+	}
+	if err := Swap(big, small); !errors.Is(err, ErrFull) {
+		t.Fatalf("invalid swap beyond the capacity %v", err)
+	}
+
+	bounded := NewConcurrentIntList(WithDomain(0, 50))
+	bounded.Insert(1)
+	if err := Swap(bounded, big); !errors.Is(err, ErrOutOfDomain) || bounded.Len() != 1 {
+		t.Fatalf("invalid swap out of the domain %v", err)
+	}
+	// the values fit even though the domains differ
+	narrow := NewConcurrentIntList(WithDomain(0, 10))
+	narrow.Insert(5)
+	if err := Swap(bounded, narrow); err != nil || !bounded.Contains(5) || !narrow.Contains(1) {
+		t.Fatalf("invalid swap of values in the domains %v", err)
+	}
+
+	desc := NewDescendingIntList()
+	desc.InsertSorted([]int{3, 2, 1})
+	if err := Swap(big, desc); !errors.Is(err, ErrIncompatible) || !slices.Equal(desc.ToSlice(), []int{3, 2, 1}) {
+		t.Fatalf("invalid swap of different orders %v", err)
+	}
+	// a custom comparator ordering the values the same way is accepted after a walk
+	custom := NewConcurrentIntList(WithComparator(func(a, b int) bool { return a < b }))
+	custom.Insert(7)
+	if err := Swap(big, custom); err != nil || big.Len() != 1 || custom.Len() != 100 || !custom.IsSorted() {
+		t.Fatalf("invalid swap with an equivalent comparator %v", err)
+	}
+}
+
+func TestTransfer(t *testing.T) {
+	a, b := newListOf(1, 2, 3), newListOf(3, 10)
+	if !Transfer(a, b, 1) || a.Contains(1) || !b.Contains(1) || a.Len() != 2 || b.Len() != 3 {
+		t.Fatalf("invalid transfer, got %v and %v", a.ToSlice(), b.ToSlice())
+	}
+	if Transfer(a, b, 1) || Transfer(a, b, 3) || !a.Contains(3) || Transfer(a, a, 2) {
+		t.Fatal("invalid transfer of an absent or duplicate value")
+	}
+	small := NewConcurrentIntList(WithCapacity(1), WithDomain(0, 5))
+	small.Insert(0)
+	if Transfer(b, small, 10) || Transfer(a, small, 2) || !a.Contains(2) || !b.Contains(10) {
+		t.Fatal("invalid transfer to a list refusing the value")
+	}
+
+	// the same value transferred by several goroutines moves once
+	a, b = newListOf(5), NewConcurrentIntList()
+	var moved int32
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if Transfer(a, b, 5) {
+				atomic.AddInt32(&moved, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	if moved != 1 || a.Contains(5) || !b.Contains(5) {
+		t.Fatalf("invalid concurrent transfers, %d moved", moved)
+	}
+
+	// transfers in both directions, a value is always in exactly one list once they are done
+	for i := 0; i < 100; i++ {
+		a.Insert(i)
+	}
+	b.Delete(5)
+	for g := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 500 {
+				if g%2 == 0 {
+					Transfer(a, b, i%100)
+				} else {
+					Transfer(b, a, i%100)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	for i := 0; i < 100; i++ {
+		if a.Contains(i) == b.Contains(i) {
+			t.Fatalf("invalid owner of %d after concurrent transfers", i)
+		}
+	}
+	if a.Len()+b.Len() != 100 || a.HealthCheck() != nil || b.HealthCheck() != nil {
+		t.Fatal("invalid lists after concurrent transfers")
+	}
+}
@@ -0,0 +1,32 @@
+package collections
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrTimeout is returned by InsertTimeout and DeleteTimeout when they give up.
+var ErrTimeout = errors.New("collections: operation timed out")
+
+func expired(deadline time.Time) bool {
+	return !deadline.IsZero() && time.Now().After(deadline)
+}
+
+// InsertTimeout is TryInsert giving up with ErrTimeout after d. The deadline is checked while
+// waiting on a frozen list and before every retry of a contended insert, an attempt in progress
+// is never interrupted, so the list stays consistent and the value is not inserted on timeout.
+func (intList *ConcurrentIntList) InsertTimeout(value int, d time.Duration) (bool, error) {
+	switch err := intList.insertUntil(value, time.Now().Add(d)); err {
+	case nil:
+		return true, nil
+	case ErrDuplicate:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// DeleteTimeout is TryDelete giving up with ErrTimeout after d, see InsertTimeout.
+func (intList *ConcurrentIntList) DeleteTimeout(value int, d time.Duration) (bool, error) {
+	return intList.deleteUntil(value, time.Now().Add(d))
+}
@@ -0,0 +1,89 @@
+package collections
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestInsertTimeout(t *testing.T) {
+	l := NewConcurrentIntList()
+	l.Insert(1)
+	l.Insert(2)
+	if ok, err := l.InsertTimeout(4, time.Second); !ok || err != nil {
+		t.Fatalf("invalid insert %v, %v", ok, err)
+	}
+	if ok, err := l.InsertTimeout(4, time.Second); ok || err != nil {
+		t.Fatalf("invalid duplicate insert %v, %v", ok, err)
+	}
+	if ok, err := l.DeleteTimeout(5, time.Second); ok || err != nil {
+		t.Fatalf("invalid delete %v, %v", ok, err)
+	}
+
+	// Hold a delete of 2 between mark and unlink, an insert of 3 right after it has to retry
+	// once the delete releases the lock, and by then its deadline is passed.
+	reached, release := make(chan struct{}), make(chan struct{})
+	testHookBeforeUnlink = func() {
+		close(reached)
+		<-release
+	}
+	go func() {
+		l.Delete(2)
+	}()
+	<-reached
+	testHookBeforeUnlink = nil
+	done := make(chan error)
+	go func() {
+		_, err := l.InsertTimeout(3, time.Millisecond)
+		done <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	if err := <-done; !errors.Is(err, ErrTimeout) {
+		t.Fatalf("invalid insert %v", err)
+	}
+	if l.Contains(3) || l.Len() != 2 || !l.IsSorted() {
+		t.Fatal("invalid list after timeout")
+	}
+
+	// A writer waiting on a frozen list gives up too.
+	l.Freeze()
+	if _, err := l.DeleteTimeout(1, time.Millisecond); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("invalid delete while frozen %v", err)
+	}
+	l.Unfreeze()
+
+	// Heavy contention never leaves the list inconsistent.
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		timeouts int
+	)
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func() {
+			for i := 0; i < 1000; i++ {
+				var err error
+				if fastrandn(2) == 0 {
+					_, err = l.InsertTimeout(int(fastrandn(8)), time.Microsecond)
+				} else {
+					_, err = l.DeleteTimeout(int(fastrandn(8)), time.Microsecond)
+				}
+				if errors.Is(err, ErrTimeout) {
+					mu.Lock()
+					timeouts++
+					mu.Unlock()
+				} else if err != nil {
+					panic(err)
+				}
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+	if !l.IsSorted() || l.Len() != len(l.ToSlice()) {
+		t.Fatal("invalid list after contention")
+	}
+	t.Logf("%d timeouts under contention", timeouts)
+}
@@ -0,0 +1,77 @@
+package collections
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// TopK returns the first k values in the list's order, the k smallest for an ascending list.
+func (intList *ConcurrentIntList) TopK(k int) []int {
+	if k <= 0 {
+		return nil
+	}
+	values := make([]int, 0, min(k, intList.Len()))
+	intList.Range(func(value int) bool {
+		values = append(values, value)
+		return len(values) < k
+	})
+	return values
+}
+
+// scored is a value with its score and its position in the walk.
+type scored struct {
+	value, score, pos int
+}
+
+// worse reports whether a ranks after b: a higher score, or the same score later in the walk.
+func (a scored) worse(b scored) bool {
+	if a.score != b.score {
+		return a.score > b.score
+	}
+	return a.pos > b.pos
+}
+
+// scoredMaxHeap keeps the worst candidate on top, of two equal scores the later one.
+type scoredMaxHeap []scored
+
+func (h scoredMaxHeap) Len() int           { return len(h) }
+func (h scoredMaxHeap) Less(i, j int) bool { return h[i].worse(h[j]) }
+func (h scoredMaxHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *scoredMaxHeap) Push(x any)        { *h = append(*h, x.(scored)) }
+func (h *scoredMaxHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// TopKBy returns the k values with the smallest score(value), ordered by ascending score.
+// Values with the same score keep the list's order. It walks the list once with a bounded
+// max-heap, so memory is O(k) regardless of the list size.
+func (intList *ConcurrentIntList) TopKBy(k int, score func(value int) int) []int {
+	if k <= 0 {
+		return nil
+	}
+	h := make(scoredMaxHeap, 0, k)
+	var pos int
+	intList.Range(func(value int) bool {
+		c := scored{value: value, score: score(value), pos: pos}
+		pos++
+		if len(h) < k {
+			heap.Push(&h, c)
+		} else if c.score < h[0].score {
+			// a later value must be strictly better to replace an equal score, keeping list order
+			h[0] = c
+			heap.Fix(&h, 0)
+		}
+		return true
+	})
+	sort.Slice(h, func(i, j int) bool {
+		return h[j].worse(h[i])
+	})
+	values := make([]int, len(h))
+	for i := range h {
+		values[i] = h[i].value
+	}
+	return values
+}
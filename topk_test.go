@@ -0,0 +1,57 @@
+package collections
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTopK(t *testing.T) {
+	l := NewConcurrentIntList()
+	for _, v := range []int{5, 1, 4, 2, 3} {
+		l.Insert(v)
+	}
+
+	if got := l.TopK(3); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("invalid top k %v", got)
+	}
+	// k exceeds Len.
+	if got := l.TopK(10); !slices.Equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Fatalf("invalid top k %v", got)
+	}
+	if got := l.TopK(0); len(got) != 0 {
+		t.Fatalf("invalid top k %v", got)
+	}
+
+	// Score reverses the natural order.
+	negate := func(v int) int { return -v }
+	if got := l.TopKBy(2, negate); !slices.Equal(got, []int{5, 4}) {
+		t.Fatalf("invalid top k by %v", got)
+	}
+	if got := l.TopKBy(10, negate); !slices.Equal(got, []int{5, 4, 3, 2, 1}) {
+		t.Fatalf("invalid top k by %v", got)
+	}
+
+	// Distance to 3, ties keep the list order.
+	distanceTo3 := func(v int) int { return distance(v, 3) }
+	if got := l.TopKBy(3, distanceTo3); !slices.Equal(got, []int{3, 2, 4}) {
+		t.Fatalf("invalid top k by %v", got)
+	}
+	if got := l.TopKBy(2, distanceTo3); !slices.Equal(got, []int{3, 2}) {
+		t.Fatalf("invalid top k by %v", got)
+	}
+	if got := l.TopKBy(0, distanceTo3); len(got) != 0 {
+		t.Fatalf("invalid top k by %v", got)
+	}
+
+	// Ties at the k boundary keep the earlier value.
+	tied := newListOf(1, 2, 3)
+	byScore := func(v int) int { return map[int]int{1: 5, 2: 5, 3: 1}[v] }
+	if got := tied.TopKBy(2, byScore); !slices.Equal(got, []int{3, 1}) {
+		t.Fatalf("invalid top k by with tied scores %v", got)
+	}
+	for k := 1; k <= 3; k++ {
+		if got := tied.TopKBy(k, func(int) int { return 0 }); !slices.Equal(got, []int{1, 2, 3}[:k]) {
+			t.Fatalf("invalid top %d by with equal scores %v", k, got)
+		}
+	}
+}
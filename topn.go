@@ -0,0 +1,63 @@
+package collections
+
+import "sync"
+
+var _ IntList = (*TopNList)(nil)
+
+// TopNList keeps the n largest values inserted into it, a streaming top-N: once it holds n
+// values, inserting a larger one evicts the smallest. Reads are lock-free on the underlying
+// ascending ConcurrentIntList, writes are serialized so that an insert and its eviction are one
+// operation for other writers.
+type TopNList struct {
+	mu   sync.Mutex
+	n    int
+	list *ConcurrentIntList
+}
+
+// NewTopNList returns a list keeping the n largest values, n lower than 1 is treated as 1.
+func NewTopNList(n int) *TopNList {
+	return &TopNList{n: max(n, 1), list: NewConcurrentIntList()}
+}
+
+// Insert adds value, evicting the smallest value if the list already holds n. It returns whether
+// value was inserted and survived: false if it was already present, or if it is not larger than
+// the smallest of n values. The new value is linked before the smallest is deleted, so a
+// concurrent reader may briefly see n+1 values.
+func (l *TopNList) Insert(value int) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.list.Len() < l.n {
+		return l.list.Insert(value)
+	}
+	smallest, _ := l.list.Min()
+	if value <= smallest || !l.list.Insert(value) {
+		return false
+	}
+	l.list.Delete(smallest)
+	return true
+}
+
+func (l *TopNList) Delete(value int) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.list.Delete(value)
+}
+
+func (l *TopNList) Contains(value int) bool {
+	return l.list.Contains(value)
+}
+
+// Range calls f for the kept values in ascending order, with the semantics of
+// ConcurrentIntList.Range.
+func (l *TopNList) Range(f func(value int) bool) {
+	l.list.Range(f)
+}
+
+func (l *TopNList) Len() int {
+	return l.list.Len()
+}
+
+// ToSlice returns the kept values in ascending order.
+func (l *TopNList) ToSlice() []int {
+	return l.list.ToSlice()
+}
@@ -0,0 +1,57 @@
+package collections
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestTopNList(t *testing.T) {
+	l := NewTopNList(3)
+	for _, value := range []int{5, 1, 9} {
+		if !l.Insert(value) {
+			t.Fatalf("%d not inserted below the bound", value)
+		}
+	}
+	if l.Insert(1) || l.Insert(0) {
+		t.Fatal("duplicate or smaller value survived")
+	}
+	if !l.Insert(7) || !slices.Equal(l.ToSlice(), []int{5, 7, 9}) || l.Contains(1) {
+		t.Fatalf("invalid eviction %v", l.ToSlice())
+	}
+	if !l.Delete(9) || !l.Insert(2) || !slices.Equal(l.ToSlice(), []int{2, 5, 7}) {
+		t.Fatalf("invalid insert after delete %v", l.ToSlice())
+	}
+
+	// Stream many values from several goroutines, the n largest are kept.
+	l = NewTopNList(50)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			for j := 0; j < 1000; j++ {
+				l.Insert((j*7919 + i*104729) % 5000)
+			}
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+	seen := make(map[int]bool)
+	for i := 0; i < 8; i++ {
+		for j := 0; j < 1000; j++ {
+			seen[(j*7919+i*104729)%5000] = true
+		}
+	}
+	var all []int
+	for value := range seen {
+		all = append(all, value)
+	}
+	slices.Sort(all)
+	if got := l.ToSlice(); !slices.Equal(got, all[len(all)-50:]) || l.Len() != 50 {
+		t.Fatalf("invalid top n %v", got)
+	}
+
+	if l := NewTopNList(0); !l.Insert(1) || !l.Insert(2) || !slices.Equal(l.ToSlice(), []int{2}) {
+		t.Fatal("invalid top n with n lower than 1")
+	}
+}
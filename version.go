@@ -0,0 +1,104 @@
+package collections
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+	"sync/atomic"
+)
+
+// ErrModifiedDuringRange is returned by RangeStrict when a write committed during the walk.
+var ErrModifiedDuringRange = errors.New("collections: list modified during range")
+
+// CurrentVersion returns the logical version of the list, it changes on every committed
+// Insert or Delete and never on reads.
+func (intList *ConcurrentIntList) CurrentVersion() uint64 {
+	return atomic.LoadUint64(&intList.version)
+}
+
+// SnapshotVersioned returns the exact contents of the list and the version they correspond to.
+// In-flight writes are waited for and new ones are held back during the copy, so a cached
+// snapshot is still accurate as long as CurrentVersion returns the same version.
+func (intList *ConcurrentIntList) SnapshotVersioned() (values []int, version uint64) {
+	intList.quiesce(func() {
+		values = intList.ToSlice()
+		version = intList.CurrentVersion()
+	})
+	return values, version
+}
+
+// RangeStrict is Range failing fast: it checks the version before every callback and stops
+// with ErrModifiedDuringRange as soon as a write has committed since the walk started, so the
+// values f received are a clean snapshot only if it returns nil. A write bumps the version
+// right after it links or unlinks its node, one still in flight when the walk ends may go
+// unreported. It returns nil when f stops the walk.
+func (intList *ConcurrentIntList) RangeStrict(f func(value int) bool) error {
+	version := intList.CurrentVersion()
+	var err error
+	stopped := false
+	intList.Range(func(value int) bool {
+		if intList.CurrentVersion() != version {
+			err = ErrModifiedDuringRange
+			return false
+		}
+		stopped = !f(value)
+		return !stopped
+	})
+	if err == nil && !stopped && intList.CurrentVersion() != version {
+		err = ErrModifiedDuringRange
+	}
+	return err
+}
+
+// ContainsToken is Contains returning a token for the version the answer was read at, pass it
+// to ValidateToken to learn whether the list changed since. The version is loaded before the
+// walk, so a write racing with it invalidates the token rather than being missed.
+func (intList *ConcurrentIntList) ContainsToken(value int) (present bool, token uint64) {
+	token = intList.CurrentVersion()
+	return intList.Contains(value), token
+}
+
+// ValidateToken reports whether no Insert or Delete committed since token was handed out.
+func (intList *ConcurrentIntList) ValidateToken(token uint64) bool {
+	return intList.CurrentVersion() == token
+}
+
+// lenCount is an exact count of the nodes at a version.
+type lenCount struct {
+	version uint64
+	n       int
+}
+
+// LenCached returns the exact number of values. The count is taken by a walk while no write is
+// in flight and cached with its version, so later calls return it without walking until an
+// Insert or Delete commits.
+func (intList *ConcurrentIntList) LenCached() int {
+	if cached, ok := intList.lenCache.Load().(lenCount); ok && cached.version == intList.CurrentVersion() {
+		return cached.n
+	}
+	var count lenCount
+	intList.quiesce(func() {
+		count.version = intList.CurrentVersion()
+		intList.Range(func(int) bool {
+			count.n++
+			return true
+		})
+	})
+	intList.lenCache.Store(count)
+	return count.n
+}
+
+// Fingerprint returns an FNV-1a hash of the values in the list's order, computed in a single
+// walk, so lists with the same contents and order share it and a cached fingerprint tells if
+// the contents changed. It is best-effort under concurrency: the walk is a Range, a write in
+// flight may or may not be included.
+func (intList *ConcurrentIntList) Fingerprint() uint64 {
+	h := fnv.New64a()
+	var buf [8]byte
+	intList.Range(func(value int) bool {
+		binary.LittleEndian.PutUint64(buf[:], uint64(value))
+		h.Write(buf[:])
+		return true
+	})
+	return h.Sum64()
+}
@@ -0,0 +1,204 @@
+package collections
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestSnapshotVersioned(t *testing.T) {
+	l := NewConcurrentIntList()
+	values, v0 := l.SnapshotVersioned()
+	if len(values) != 0 || v0 != l.CurrentVersion() {
+		t.Fatal("invalid empty snapshot")
+	}
+
+	l.Insert(2)
+	l.Insert(1)
+	values, v1 := l.SnapshotVersioned()
+	if v1 == v0 || len(values) != 2 || values[0] != 1 || values[1] != 2 {
+		t.Fatalf("invalid snapshot %v at %d", values, v1)
+	}
+
+	// Pure reads and refused writes don't move the version.
+	l.Contains(1)
+	l.Range(func(int) bool { return true })
+	l.ToSlice()
+	l.Insert(1)
+	l.Delete(3)
+	if l.CurrentVersion() != v1 {
+		t.Fatal("version changed without mutation")
+	}
+	if _, v := l.SnapshotVersioned(); v != v1 {
+		t.Fatal("version changed without mutation")
+	}
+
+	l.Delete(1)
+	if l.CurrentVersion() == v1 {
+		t.Fatal("version unchanged after delete")
+	}
+
+	// Snapshot taken while frozen.
+	l.Freeze()
+	values, v2 := l.SnapshotVersioned()
+	l.Unfreeze()
+	if v2 != l.CurrentVersion() || len(values) != 1 || values[0] != 2 {
+		t.Fatal("invalid snapshot while frozen")
+	}
+
+	// Every snapshot is exact for its version under concurrent writes.
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			for i := 0; i < 1000; i++ {
+				l.Insert(int(fastrandn(64)))
+				l.Delete(int(fastrandn(64)))
+			}
+			wg.Done()
+		}()
+	}
+	seen := make(map[uint64]int)
+	for i := 0; i < 100; i++ {
+		values, v := l.SnapshotVersioned()
+		if n, ok := seen[v]; ok && n != len(values) {
+			t.Fatalf("two snapshots of version %d differ", v)
+		}
+		seen[v] = len(values)
+	}
+	wg.Wait()
+}
+
+func TestRangeStrict(t *testing.T) {
+	l := newListOf(1, 2, 3, 4)
+	var got []int
+	if err := l.RangeStrict(func(value int) bool {
+		got = append(got, value)
+		return true
+	}); err != nil || !slices.Equal(got, []int{1, 2, 3, 4}) {
+		t.Fatalf("invalid clean walk %v, %v", got, err)
+	}
+
+	// modified mid-walk, the walk stops at the next value
+	got = got[:0]
+	err := l.RangeStrict(func(value int) bool {
+		got = append(got, value)
+		if value == 2 {
+			l.Delete(4)
+		}
+		return true
+	})
+	if !errors.Is(err, ErrModifiedDuringRange) || !slices.Equal(got, []int{1, 2}) {
+		t.Fatalf("invalid walk with a delete %v, %v", got, err)
+	}
+	// modified by the last callback
+	err = l.RangeStrict(func(value int) bool {
+		if value == 3 {
+			l.Insert(0)
+		}
+		return true
+	})
+	if !errors.Is(err, ErrModifiedDuringRange) {
+		t.Fatalf("invalid walk with a last insert %v", err)
+	}
+	// stopped by f before the write is noticed
+	if err := l.RangeStrict(func(value int) bool {
+		l.Insert(10)
+		return false
+	}); err != nil {
+		t.Fatalf("invalid walk stopped by f %v", err)
+	}
+}
+
+func TestContainsToken(t *testing.T) {
+	l := NewConcurrentIntList()
+	l.Insert(1)
+
+	present, token := l.ContainsToken(1)
+	if !present || !l.ValidateToken(token) {
+		t.Fatal("invalid token without mutation")
+	}
+	l.Contains(2)
+	l.Insert(1)
+	l.Delete(3)
+	if !l.ValidateToken(token) {
+		t.Fatal("invalid token after failed writes")
+	}
+
+	l.Insert(2)
+	if l.ValidateToken(token) {
+		t.Fatal("invalid token after insert")
+	}
+	present, token = l.ContainsToken(2)
+	if !present {
+		t.Fatal("invalid contains token")
+	}
+	l.Delete(2)
+	if l.ValidateToken(token) {
+		t.Fatal("invalid token after delete")
+	}
+}
+
+func TestLenCached(t *testing.T) {
+	l := NewConcurrentIntList()
+	if n := l.LenCached(); n != 0 {
+		t.Fatalf("invalid cached len expected %d, got %d", 0, n)
+	}
+	for i := 0; i < 10; i++ {
+		l.Insert(i)
+	}
+	if n := l.LenCached(); n != 10 {
+		t.Fatalf("invalid cached len expected %d, got %d", 10, n)
+	}
+
+	// Link a node by hand without bumping the version, an idle list must not be walked again.
+	last := l.root.next()
+	for last.next() != nil {
+		last = last.next()
+	}
+	last.updateNext(newIntNode(100))
+	if n := l.LenCached(); n != 10 {
+		t.Fatalf("invalid cache hit expected %d, got %d", 10, n)
+	}
+	l.Delete(0)
+	if n := l.LenCached(); n != 10 {
+		t.Fatalf("invalid recount after delete expected %d, got %d", 10, n)
+	}
+	l.Insert(-1)
+	if n := l.LenCached(); n != 11 {
+		t.Fatalf("invalid recount after insert expected %d, got %d", 11, n)
+	}
+	l.Insert(-1)
+	if n := l.LenCached(); n != 11 {
+		t.Fatalf("invalid cached len after failed insert expected %d, got %d", 11, n)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	a, b := newListOf(1, 2, 3), newListOf(3, 1, 2)
+	if a.Fingerprint() != b.Fingerprint() || a.Fingerprint() != a.Fingerprint() {
+		t.Fatal("invalid fingerprint of equal lists")
+	}
+	if NewConcurrentIntList().Fingerprint() != newListOf().Fingerprint() {
+		t.Fatal("invalid fingerprint of empty lists")
+	}
+	before := a.Fingerprint()
+	for _, change := range []func(){
+		func() { a.Insert(4) },
+		func() { a.Delete(4); a.Delete(2) },
+		func() { a.Insert(2); a.Delete(3); a.Insert(-3) },
+	} {
+		change()
+		if a.Fingerprint() == before {
+			t.Fatalf("invalid fingerprint after a change, got %v", a.ToSlice())
+		}
+		before = a.Fingerprint()
+	}
+	// order dependent
+	d := NewDescendingIntList()
+	d.InsertSortedDesc([]int{1, 2, 3})
+	if d.Fingerprint() == b.Fingerprint() {
+		t.Fatal("invalid fingerprint of a reversed list")
+	}
+}
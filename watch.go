@@ -0,0 +1,102 @@
+package collections
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// minWatchBuffer is the number of minimums a WatchMin channel holds for a slow consumer.
+const minWatchBuffer = 8
+
+// minWatch holds the WatchMin channels and the last minimum sent to them.
+type minWatch struct {
+	mu       sync.Mutex
+	channels map[chan int]struct{}
+	// count is len(channels), read by the writers without the lock
+	count   int32
+	last    int
+	hasLast bool
+}
+
+// WatchMin returns a channel receiving the first value of the list, Min, each time it changes
+// after WatchMin returns: when a value is inserted before the head or the head is deleted, by
+// any write. Nothing is sent while the list is empty. The channel is buffered and a slow
+// consumer loses the oldest minimums, so the last one received is always the latest. Calling
+// the returned func unsubscribes and closes the channel, Close closes every channel and a
+// channel returned after Close is already closed. Every write checks the head while a channel is
+// subscribed.
+func (intList *ConcurrentIntList) WatchMin() (<-chan int, func()) {
+	ch := make(chan int, minWatchBuffer)
+	w := &intList.minWatch
+	w.mu.Lock()
+	if intList.Closed() {
+		// checked under the lock, Close takes it after closing the list
+		w.mu.Unlock()
+		close(ch)
+		return ch, func() {}
+	}
+	if w.channels == nil {
+		w.channels = make(map[chan int]struct{})
+	}
+	w.channels[ch] = struct{}{}
+	if atomic.AddInt32(&w.count, 1) == 1 {
+		// read after the count is published, a write missing the new channel is seen here
+		w.last, w.hasLast = intList.Min()
+	}
+	w.mu.Unlock()
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			w.mu.Lock()
+			// Close may have closed it already
+			if _, ok := w.channels[ch]; ok {
+				delete(w.channels, ch)
+				atomic.AddInt32(&w.count, -1)
+				close(ch)
+			}
+			w.mu.Unlock()
+		})
+	}
+}
+
+// closeWatches closes and unsubscribes every WatchMin channel, once the list is closed.
+func (intList *ConcurrentIntList) closeWatches() {
+	w := &intList.minWatch
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for ch := range w.channels {
+		delete(w.channels, ch)
+		close(ch)
+	}
+	atomic.StoreInt32(&w.count, 0)
+}
+
+// notifyMin sends the minimum to the WatchMin channels if it changed, it is called after every
+// committed write. The minimum is read under the lock, so the last one sent is that of the
+// latest write.
+func (intList *ConcurrentIntList) notifyMin() {
+	w := &intList.minWatch
+	if atomic.LoadInt32(&w.count) == 0 {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	value, ok := intList.Min()
+	if !ok || (w.hasLast && value == w.last) {
+		w.hasLast = ok
+		return
+	}
+	w.last, w.hasLast = value, true
+	for ch := range w.channels {
+		select {
+		case ch <- value:
+		default:
+			// full, drop the oldest, the consumer may have made room meanwhile
+			select {
+			case <-ch:
+			default:
+			}
+			ch <- value
+		}
+	}
+}
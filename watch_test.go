@@ -0,0 +1,106 @@
+package collections
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestWatchMin(t *testing.T) {
+	l := newListOf(5, 8)
+	mins, stop := l.WatchMin()
+	receive := func(want int) {
+		t.Helper()
+		select {
+		case got := <-mins:
+			if got != want {
+				t.Fatalf("invalid minimum expected %d, got %d", want, got)
+			}
+		default:
+			t.Fatalf("no minimum sent, expected %d", want)
+		}
+	}
+
+	l.Insert(7)
+	l.Insert(9)
+	l.Delete(8)
+	if len(mins) != 0 {
+		t.Fatalf("invalid minimum %d sent without a head change", <-mins)
+	}
+	l.Insert(3)
+	receive(3)
+	l.Delete(3)
+	receive(5)
+	l.Delete(5)
+	l.Delete(7)
+	receive(7)
+	receive(9)
+	// an empty list sends nothing, its next head does
+	l.Delete(9)
+	l.Insert(9)
+	receive(9)
+	l.ReplaceAll([]int{4, 1})
+	receive(1)
+
+	// a slow consumer keeps the latest minimums
+	for i := 0; i < 3*minWatchBuffer; i++ {
+		l.Insert(-i - 1)
+	}
+	if len(mins) != minWatchBuffer {
+		t.Fatalf("invalid buffered count %d", len(mins))
+	}
+	for i := 2*minWatchBuffer + 1; i <= 3*minWatchBuffer; i++ {
+		receive(-i)
+	}
+
+	stop()
+	stop()
+	l.Insert(-100)
+	if _, ok := <-mins; ok {
+		t.Fatal("invalid minimum after unsubscribe")
+	}
+
+	// concurrent writers, the last minimum received is the final one
+	mins, stop = l.WatchMin()
+	defer stop()
+	var wg sync.WaitGroup
+	for g := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 100 {
+				l.Insert(-1000 - i*4 - g)
+			}
+		}()
+	}
+	wg.Wait()
+	var last int
+	for len(mins) > 0 {
+		last = <-mins
+	}
+	if want, _ := l.Min(); last != want {
+		t.Fatalf("invalid last minimum expected %d, got %d", want, last)
+	}
+}
+
+func TestWatchMinClose(t *testing.T) {
+	l := newListOf(5, 8)
+	mins, stop := l.WatchMin()
+	l.Insert(3)
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// the pending minimum is still received, then the channel is closed
+	if got, ok := <-mins; !ok || got != 3 {
+		t.Fatalf("invalid minimum expected %d, got %d", 3, got)
+	}
+	if _, ok := <-mins; ok {
+		t.Fatal("invalid minimum after close")
+	}
+	stop()
+
+	late, stopLate := l.WatchMin()
+	if _, ok := <-late; ok {
+		t.Fatal("invalid minimum on a channel returned after close")
+	}
+	stopLate()
+}